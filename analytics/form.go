@@ -0,0 +1,204 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/avalonbits/fball/object"
+	"github.com/avalonbits/fball/standings"
+)
+
+// Venue selects fixtures by where the team played.
+type Venue int
+
+const (
+	AnyVenue Venue = iota
+	HomeOnly
+	AwayOnly
+)
+
+// FormOptions select the fixtures used to compute the form of a team.
+type FormOptions struct {
+	// From and To limit the fixtures to those that kicked off in [From, To). Zero values
+	// leave the window open.
+	From time.Time
+	To   time.Time
+
+	Venue Venue
+
+	// Last limits the fixtures to the most recent ones in the window. Zero uses all of them.
+	Last int
+
+	// Points has the points for each result. If all of them are zero, the points of
+	// standings.DefaultConfig are used.
+	Points standings.Config
+}
+
+// Form is the record of a team over a set of fixtures.
+type Form struct {
+	Team int
+
+	// Results has one W, D or L per fixture, the most recent last.
+	Results string
+
+	Played        int
+	Won           int
+	Drawn         int
+	Lost          int
+	Points        int
+	GoalsFor      int
+	GoalsAgainst  int
+	CleanSheets   int
+	FailedToScore int
+
+	// Current runs, up to the most recent fixture.
+	Unbeaten int
+	Winless  int
+	Winning  int
+	Losing   int
+
+	// Longest runs.
+	LongestUnbeaten int
+	LongestWinless  int
+	LongestWinning  int
+	LongestLosing   int
+}
+
+// PointsPerGame returns the average number of points per fixture.
+func (f Form) PointsPerGame() float64 {
+	if f.Played == 0 {
+		return 0
+	}
+	return float64(f.Points) / float64(f.Played)
+}
+
+// TeamForm computes the form of team over the finished fixtures selected by opts.
+func TeamForm(fixtures []object.FixtureDetail, team int, opts FormOptions) Form {
+	points := opts.Points
+	if points.Win == 0 && points.Draw == 0 && points.Loss == 0 {
+		points = standings.DefaultConfig()
+	}
+	form := Form{Team: team}
+	for _, f := range teamFixtures(fixtures, team, opts) {
+		scored, conceded := f.Goals.Home, f.Goals.Away
+		if f.Teams.Away.ID == team {
+			scored, conceded = conceded, scored
+		}
+		form.add(points, scored, conceded)
+	}
+	return form
+}
+
+// FormPoint is the form of a team over the fixtures up to and including FixtureID.
+type FormPoint struct {
+	FixtureID int
+	Kickoff   time.Time
+	Form      Form
+}
+
+// RollingForm returns, after each of the fixtures of team selected by opts, its form over the
+// last n fixtures.
+func RollingForm(fixtures []object.FixtureDetail, team, n int, opts FormOptions) []FormPoint {
+	selected := teamFixtures(fixtures, team, opts)
+	window := opts
+	window.From, window.To, window.Last = time.Time{}, time.Time{}, n
+
+	points := make([]FormPoint, len(selected))
+	for i, f := range selected {
+		points[i] = FormPoint{
+			FixtureID: f.Fixture.ID,
+			Kickoff:   f.Fixture.Kickoff(),
+			Form:      TeamForm(selected[:i+1], team, window),
+		}
+	}
+	return points
+}
+
+// teamFixtures returns the finished fixtures of team selected by opts, oldest first.
+func teamFixtures(fixtures []object.FixtureDetail, team int, opts FormOptions) []object.FixtureDetail {
+	selected := []object.FixtureDetail{}
+	for _, f := range fixtures {
+		if !f.Fixture.Status.Short.IsFinished() {
+			continue
+		}
+		home, away := f.Teams.Home.ID == team, f.Teams.Away.ID == team
+		if !(home && opts.Venue != AwayOnly) && !(away && opts.Venue != HomeOnly) {
+			continue
+		}
+		kickoff := f.Fixture.Kickoff()
+		if !opts.From.IsZero() && kickoff.Before(opts.From) {
+			continue
+		}
+		if !opts.To.IsZero() && !kickoff.Before(opts.To) {
+			continue
+		}
+		selected = append(selected, f)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Fixture.Timestamp < selected[j].Fixture.Timestamp
+	})
+	if opts.Last > 0 && len(selected) > opts.Last {
+		selected = selected[len(selected)-opts.Last:]
+	}
+	return selected
+}
+
+func (f *Form) add(points standings.Config, scored, conceded int) {
+	f.Played++
+	f.GoalsFor += scored
+	f.GoalsAgainst += conceded
+	if conceded == 0 {
+		f.CleanSheets++
+	}
+	if scored == 0 {
+		f.FailedToScore++
+	}
+
+	switch {
+	case scored > conceded:
+		f.Won++
+		f.Points += points.Win
+		f.Results += "W"
+		f.Unbeaten++
+		f.Winning++
+		f.Winless, f.Losing = 0, 0
+	case scored == conceded:
+		f.Drawn++
+		f.Points += points.Draw
+		f.Results += "D"
+		f.Unbeaten++
+		f.Winless++
+		f.Winning, f.Losing = 0, 0
+	default:
+		f.Lost++
+		f.Points += points.Loss
+		f.Results += "L"
+		f.Winless++
+		f.Losing++
+		f.Unbeaten, f.Winning = 0, 0
+	}
+
+	f.LongestUnbeaten = max(f.LongestUnbeaten, f.Unbeaten)
+	f.LongestWinless = max(f.LongestWinless, f.Winless)
+	f.LongestWinning = max(f.LongestWinning, f.Winning)
+	f.LongestLosing = max(f.LongestLosing, f.Losing)
+}
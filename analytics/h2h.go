@@ -0,0 +1,253 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/avalonbits/fball"
+	"github.com/avalonbits/fball/object"
+)
+
+// recentLength is the number of results in H2HSummary.Recent.
+const recentLength = 5
+
+// H2HSide is the head to head record of one of the teams.
+type H2HSide struct {
+	Team     object.TeamData
+	Wins     int
+	HomeWins int
+	AwayWins int
+	Goals    int
+
+	// BiggestWin is the fixture the team won by the largest margin, if any.
+	BiggestWin *object.FixtureDetail
+
+	// Averages per fixture over the fixtures with embedded statistics. They are not valid if
+	// no fixture had them.
+	YellowCards object.NullFloat
+	RedCards    object.NullFloat
+	Corners     object.NullFloat
+}
+
+// VenueRecord is the head to head record at a venue. Wins are in the same order as
+// H2HSummary.Teams.
+type VenueRecord struct {
+	Venue  object.Venue
+	Played int
+	Wins   [2]int
+	Draws  int
+}
+
+// H2HSummary aggregates the finished fixtures between two teams.
+type H2HSummary struct {
+	Teams  [2]H2HSide
+	Played int
+	Draws  int
+
+	// Venues has the record at each venue, most used first. Fixtures at unknown venues, with
+	// neither an id nor a name, are not included.
+	Venues []VenueRecord
+
+	// Recent has the results of the last fixtures from the point of view of the first team,
+	// as W, D or L, the most recent last.
+	Recent string
+}
+
+type statTotals struct {
+	fixtures int
+	yellow   int
+	red      int
+	corners  int
+}
+
+// SummarizeHead2Head aggregates the fixtures between teamA and teamB in r.
+func SummarizeHead2Head(r object.Head2HeadResponse, teamA, teamB int) H2HSummary {
+	fixtures := append([]object.FixtureDetail(nil), r.Head2Head...)
+	sort.SliceStable(fixtures, func(i, j int) bool {
+		return fixtures[i].Fixture.Timestamp < fixtures[j].Fixture.Timestamp
+	})
+
+	sum := H2HSummary{}
+	sides := map[int]int{teamA: 0, teamB: 1}
+	stats := [2]statTotals{}
+	venues := map[venueKey]*VenueRecord{}
+	venueOrder := []venueKey{}
+	margins := [2]int{}
+	results := []byte{}
+
+	for i := range fixtures {
+		f := &fixtures[i]
+		home, okHome := sides[f.Teams.Home.ID]
+		away, okAway := sides[f.Teams.Away.ID]
+		if !okHome || !okAway || home == away || !f.Fixture.Status.Short.IsFinished() {
+			continue
+		}
+		sum.Played++
+		sum.Teams[home].Team = teamData(f.Teams.Home)
+		sum.Teams[away].Team = teamData(f.Teams.Away)
+		sum.Teams[home].Goals += f.Goals.Home
+		sum.Teams[away].Goals += f.Goals.Away
+
+		// Fixtures at unknown venues are counted in a record that is thrown away.
+		v := &VenueRecord{}
+		if key, ok := venueKeyOf(f.Fixture.Venue); ok {
+			if v, ok = venues[key]; !ok {
+				v = &VenueRecord{Venue: f.Fixture.Venue}
+				venues[key] = v
+				venueOrder = append(venueOrder, key)
+			}
+		}
+		v.Played++
+
+		winner, margin := -1, f.Goals.Home-f.Goals.Away
+		switch {
+		case margin > 0:
+			winner = home
+			sum.Teams[home].HomeWins++
+		case margin < 0:
+			winner, margin = away, -margin
+			sum.Teams[away].AwayWins++
+		default:
+			sum.Draws++
+			v.Draws++
+		}
+		if winner >= 0 {
+			sum.Teams[winner].Wins++
+			v.Wins[winner]++
+			if margin > margins[winner] {
+				margins[winner] = margin
+				sum.Teams[winner].BiggestWin = f
+			}
+		}
+
+		switch winner {
+		case 0:
+			results = append(results, 'W')
+		case 1:
+			results = append(results, 'L')
+		default:
+			results = append(results, 'D')
+		}
+
+		for _, s := range f.Statistics {
+			side, ok := sides[s.Team.ID]
+			if !ok {
+				continue
+			}
+			ts := s.Typed()
+			stats[side].fixtures++
+			stats[side].yellow += ts.YellowCards.Value
+			stats[side].red += ts.RedCards.Value
+			stats[side].corners += ts.CornerKicks.Value
+		}
+	}
+
+	for side := range stats {
+		if n := float64(stats[side].fixtures); n > 0 {
+			sum.Teams[side].YellowCards = object.Float(float64(stats[side].yellow) / n)
+			sum.Teams[side].RedCards = object.Float(float64(stats[side].red) / n)
+			sum.Teams[side].Corners = object.Float(float64(stats[side].corners) / n)
+		}
+	}
+	if sum.Teams[0].Team.ID == 0 {
+		sum.Teams[0].Team.ID = teamA
+	}
+	if sum.Teams[1].Team.ID == 0 {
+		sum.Teams[1].Team.ID = teamB
+	}
+
+	for _, key := range venueOrder {
+		sum.Venues = append(sum.Venues, *venues[key])
+	}
+	sort.SliceStable(sum.Venues, func(i, j int) bool {
+		return sum.Venues[i].Played > sum.Venues[j].Played
+	})
+
+	if len(results) > recentLength {
+		results = results[len(results)-recentLength:]
+	}
+	sum.Recent = string(results)
+	return sum
+}
+
+// venueKey identifies a venue. The api often sends no venue id, so venues without one are
+// identified by name and city.
+type venueKey struct {
+	id   int
+	name string
+	city string
+}
+
+// venueKeyOf returns the key of v. ok is false if the venue is unknown.
+func venueKeyOf(v object.Venue) (key venueKey, ok bool) {
+	if v.ID != 0 {
+		return venueKey{id: v.ID}, true
+	}
+	if v.Name == "" {
+		return venueKey{}, false
+	}
+	return venueKey{name: strings.ToLower(v.Name), city: strings.ToLower(v.City)}, true
+}
+
+func teamData(t object.FixtureTeam) object.TeamData {
+	return object.TeamData{ID: t.ID, Name: t.Name, Logo: t.Logo}
+}
+
+// FindTeam searches for a team by name with Client.TeamInfo. An exact, case insensitive, match
+// is preferred over a partial one.
+func FindTeam(ctx context.Context, c *fball.Client, name string) (object.TeamData, error) {
+	tir, err := c.TeamInfo(ctx, fball.TeamInfoParams{Search: name})
+	if err != nil {
+		return object.TeamData{}, err
+	}
+	if len(tir.TeamInfo) == 0 {
+		return object.TeamData{}, fmt.Errorf("team %q not found", name)
+	}
+	for _, ti := range tir.TeamInfo {
+		if strings.EqualFold(ti.Team.Name, name) {
+			return ti.Team, nil
+		}
+	}
+	return tir.TeamInfo[0].Team, nil
+}
+
+// Head2HeadByName resolves the team names with FindTeam, fetches their fixtures with
+// Client.Head2Head and summarizes them. The H2H field of params is set from the resolved teams.
+func Head2HeadByName(ctx context.Context, c *fball.Client, nameA, nameB string, params fball.Head2HeadParams) (H2HSummary, error) {
+	a, err := FindTeam(ctx, c, nameA)
+	if err != nil {
+		return H2HSummary{}, err
+	}
+	b, err := FindTeam(ctx, c, nameB)
+	if err != nil {
+		return H2HSummary{}, err
+	}
+
+	params.H2H = strconv.Itoa(a.ID) + "-" + strconv.Itoa(b.ID)
+	h2h, err := c.Head2Head(ctx, params)
+	if err != nil {
+		return H2HSummary{}, err
+	}
+	return SummarizeHead2Head(h2h, a.ID, b.ID), nil
+}
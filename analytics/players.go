@@ -0,0 +1,207 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analytics
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/avalonbits/fball"
+	"github.com/avalonbits/fball/object"
+)
+
+// Metric is a player stat counted across fixtures.
+type Metric string
+
+const (
+	Goals         Metric = "goals"
+	Assists       Metric = "assists"
+	Shots         Metric = "shots"
+	ShotsOnTarget Metric = "shots_on_target"
+	KeyPasses     Metric = "key_passes"
+	Duels         Metric = "duels"
+	DuelsWon      Metric = "duels_won"
+	Dribbles      Metric = "dribbles"
+	DribblesWon   Metric = "dribbles_won"
+	Tackles       Metric = "tackles"
+	Interceptions Metric = "interceptions"
+	YellowCards   Metric = "yellow_cards"
+	RedCards      Metric = "red_cards"
+)
+
+// Metrics lists all metrics.
+var Metrics = []Metric{
+	Goals, Assists, Shots, ShotsOnTarget, KeyPasses, Duels, DuelsWon, Dribbles, DribblesWon,
+	Tackles, Interceptions, YellowCards, RedCards,
+}
+
+// PlayerSeason is the record of a player across the fixtures of a season.
+type PlayerSeason struct {
+	Player object.Player
+	Team   object.TeamData
+
+	// Position is the position group from Games.Position, e.g. "G", "D", "M" or "F", of the
+	// last fixture the player played.
+	Position string
+
+	Appearances int
+	Minutes     int
+	Totals      map[Metric]int
+
+	// Percentiles has, for each metric, the percentage of players in the same position group
+	// with a lower per 90 rate, counting ties as half. It is nil for players below
+	// PlayerSeasonOptions.MinMinutes.
+	Percentiles map[Metric]float64
+}
+
+// Per90 returns the rate of m per 90 minutes played.
+func (p PlayerSeason) Per90(m Metric) float64 {
+	if p.Minutes == 0 {
+		return 0
+	}
+	return float64(p.Totals[m]) * 90 / float64(p.Minutes)
+}
+
+// PlayerSeasonOptions configure AggregatePlayers.
+type PlayerSeasonOptions struct {
+	// MinMinutes is the minimum number of minutes for a player to be ranked in percentiles.
+	// Per 90 rates of players with few minutes are mostly noise.
+	MinMinutes int
+}
+
+// AggregatePlayers sums the stats of each player in stats, e.g. the teams of the
+// PlayerStatsResponse of every fixture of a season, and ranks the per 90 rates of each position
+// group. Players are returned in the order they first appear.
+func AggregatePlayers(stats []object.PlayerStats, opts PlayerSeasonOptions) []PlayerSeason {
+	totals := object.TotalPlayerStats(stats)
+	players := make([]PlayerSeason, 0, len(totals))
+	groups := map[string][]int{}
+	for _, t := range totals {
+		s := t.Stats
+		p := PlayerSeason{
+			Player:      t.Player,
+			Team:        t.Team,
+			Position:    s.Games.Position,
+			Appearances: t.Appearances,
+			Minutes:     s.Games.Minutes.Value,
+			Totals: map[Metric]int{
+				Goals:         s.Goals.Total.Value,
+				Assists:       s.Goals.Assists.Value,
+				Shots:         s.Shots.Total.Value,
+				ShotsOnTarget: s.Shots.On.Value,
+				KeyPasses:     s.Passes.Key.Value,
+				Duels:         s.Duels.Total.Value,
+				DuelsWon:      s.Duels.Won.Value,
+				Dribbles:      s.Dribbles.Attempts.Value,
+				DribblesWon:   s.Dribbles.Success.Value,
+				Tackles:       s.Tackles.Total.Value,
+				Interceptions: s.Tackles.Interceptions.Value,
+				YellowCards:   s.Cards.Yellow.Value,
+				RedCards:      s.Cards.Red.Value,
+			},
+		}
+		if p.Position == "" {
+			p.Position = t.Player.Pos
+		}
+		if p.Minutes > 0 && p.Minutes >= opts.MinMinutes {
+			groups[p.Position] = append(groups[p.Position], len(players))
+		}
+		players = append(players, p)
+	}
+
+	for _, group := range groups {
+		for _, i := range group {
+			players[i].Percentiles = make(map[Metric]float64, len(Metrics))
+		}
+		for _, m := range Metrics {
+			for _, i := range group {
+				rate := players[i].Per90(m)
+				below := 0.0
+				for _, j := range group {
+					switch other := players[j].Per90(m); {
+					case other < rate:
+						below++
+					case other == rate && i != j:
+						below += 0.5
+					}
+				}
+				pct := 100.0
+				if len(group) > 1 {
+					pct = 100 * below / float64(len(group)-1)
+				}
+				players[i].Percentiles[m] = pct
+			}
+		}
+	}
+	return players
+}
+
+// Leaderboard returns the players of position, or of every position if empty, sorted by m,
+// highest first. If per90 is set, players are sorted by their per 90 rate and only those with
+// percentiles, i.e. enough minutes, are included.
+func Leaderboard(players []PlayerSeason, m Metric, per90 bool, position string) []PlayerSeason {
+	board := []PlayerSeason{}
+	for _, p := range players {
+		if position != "" && p.Position != position {
+			continue
+		}
+		if per90 && p.Percentiles == nil {
+			continue
+		}
+		board = append(board, p)
+	}
+	value := func(p PlayerSeason) float64 {
+		if per90 {
+			return p.Per90(m)
+		}
+		return float64(p.Totals[m])
+	}
+	sort.SliceStable(board, func(i, j int) bool {
+		return value(board[i]) > value(board[j])
+	})
+	return board
+}
+
+// LoadPlayerStats fetches the player stats of every played fixture in fixtures with
+// Client.PlayerStats, using at most workers concurrent requests.
+func LoadPlayerStats(ctx context.Context, c *fball.Client, fixtures []object.FixtureDetail, workers int) ([]object.PlayerStats, error) {
+	reqs := []fball.BulkRequest{}
+	for _, f := range fixtures {
+		if !f.Fixture.Status.Short.IsPlayed() {
+			continue
+		}
+		params := fball.PlayerStatsParams{Fixture: strconv.Itoa(f.Fixture.ID)}
+		reqs = append(reqs, fball.NewBulkRequest((*fball.Client).PlayerStats, params))
+	}
+
+	results, err := c.Bulk(ctx, workers, reqs)
+	if err != nil {
+		return nil, err
+	}
+	stats := []object.PlayerStats{}
+	for _, res := range results {
+		psr, err := fball.BulkValue[object.PlayerStatsResponse](res)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, psr.PlayerStats...)
+	}
+	return stats, nil
+}
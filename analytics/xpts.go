@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package analytics computes team and player analytics from fixture data.
+package analytics
+
+import (
+	"sort"
+
+	"github.com/avalonbits/fball/object"
+	"github.com/avalonbits/fball/poisson"
+	"github.com/avalonbits/fball/standings"
+)
+
+// xgMaxGoals is the highest number of goals per team considered when simulating a fixture
+// from its expected goals.
+const xgMaxGoals = 10
+
+// FixtureXPts returns the expected points of each team in a finished fixture, computed from
+// the probability of every scoreline when both teams score as many goals as their expected
+// goals say they should. ok is false if the fixture has no expected goals for both teams,
+// which are only embedded in fixtures fetched by id.
+func FixtureXPts(f object.FixtureDetail, cfg standings.Config) (home, away float64, ok bool) {
+	xgHome, xgAway, ok := fixtureXG(f)
+	if !ok {
+		return 0, 0, false
+	}
+	pHome, pDraw, pAway := poisson.NewMatrix(xgHome, xgAway, 0, xgMaxGoals).Outcome()
+	home = pHome*float64(cfg.Win) + pDraw*float64(cfg.Draw) + pAway*float64(cfg.Loss)
+	away = pAway*float64(cfg.Win) + pDraw*float64(cfg.Draw) + pHome*float64(cfg.Loss)
+	return home, away, true
+}
+
+func fixtureXG(f object.FixtureDetail) (home, away float64, ok bool) {
+	var homeOK, awayOK bool
+	for _, s := range f.Statistics {
+		xg := s.Typed().ExpectedGoals
+		if !xg.Valid {
+			continue
+		}
+		switch s.Team.ID {
+		case f.Teams.Home.ID:
+			home, homeOK = xg.Value, true
+		case f.Teams.Away.ID:
+			away, awayOK = xg.Value, true
+		}
+	}
+	return home, away, homeOK && awayOK
+}
+
+// XPtsRow is the expected points record of a team. It only covers fixtures with expected goals,
+// so Played and Points can be lower than in the league table.
+type XPtsRow struct {
+	Team         object.TeamData
+	Played       int
+	Points       int
+	XPoints      float64
+	GoalsFor     int
+	GoalsAgainst int
+	XGFor        float64
+	XGAgainst    float64
+}
+
+// Luck is how many more points the team got than expected. Negative values mean the team
+// under-performed.
+func (r XPtsRow) Luck() float64 {
+	return float64(r.Points) - r.XPoints
+}
+
+// XPtsTable computes the expected points of each team over the finished fixtures with expected
+// goals. Rows are sorted by expected points, highest first.
+func XPtsTable(fixtures []object.FixtureDetail, cfg standings.Config) []XPtsRow {
+	rows := map[int]*XPtsRow{}
+	row := func(team object.FixtureTeam) *XPtsRow {
+		r, ok := rows[team.ID]
+		if !ok {
+			r = &XPtsRow{Team: object.TeamData{ID: team.ID, Name: team.Name, Logo: team.Logo}}
+			rows[team.ID] = r
+		}
+		return r
+	}
+
+	for _, f := range fixtures {
+		if !f.Fixture.Status.Short.IsPlayed() {
+			continue
+		}
+		xptsHome, xptsAway, ok := FixtureXPts(f, cfg)
+		if !ok {
+			continue
+		}
+		xgHome, xgAway, _ := fixtureXG(f)
+
+		home, away := row(f.Teams.Home), row(f.Teams.Away)
+		home.add(cfg, f.Goals.Home, f.Goals.Away, xgHome, xgAway, xptsHome)
+		away.add(cfg, f.Goals.Away, f.Goals.Home, xgAway, xgHome, xptsAway)
+	}
+
+	table := make([]XPtsRow, 0, len(rows))
+	for _, r := range rows {
+		table = append(table, *r)
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].XPoints != table[j].XPoints {
+			return table[i].XPoints > table[j].XPoints
+		}
+		return table[i].Team.ID < table[j].Team.ID
+	})
+	return table
+}
+
+func (r *XPtsRow) add(cfg standings.Config, scored, conceded int, xgFor, xgAgainst, xpts float64) {
+	r.Played++
+	r.GoalsFor += scored
+	r.GoalsAgainst += conceded
+	r.XGFor += xgFor
+	r.XGAgainst += xgAgainst
+	r.XPoints += xpts
+	switch {
+	case scored > conceded:
+		r.Points += cfg.Win
+	case scored == conceded:
+		r.Points += cfg.Draw
+	default:
+		r.Points += cfg.Loss
+	}
+}
+
+// XPtsComparison compares the points of a team in a league table with its expected points.
+type XPtsComparison struct {
+	Ranking object.Ranking
+	XPts    XPtsRow
+}
+
+// Complete returns true if every fixture in the table has expected goals, so the expected
+// points cover the same fixtures as the table.
+func (c XPtsComparison) Complete() bool {
+	return c.XPts.Played == c.Ranking.All.Played
+}
+
+// Diff is how many more points the team got than expected. Both come from the fixtures with
+// expected goals, so teams are comparable even if some fixtures have none. See Complete.
+func (c XPtsComparison) Diff() float64 {
+	return c.XPts.Luck()
+}
+
+// CompareXPts matches the rankings of a table, e.g. from Client.Standings, with the expected
+// points rows of the same teams. The result is sorted by Diff, from the biggest
+// over-performer to the biggest under-performer. Teams missing from either side are skipped.
+func CompareXPts(rankings []object.Ranking, rows []XPtsRow) []XPtsComparison {
+	byTeam := make(map[int]XPtsRow, len(rows))
+	for _, r := range rows {
+		byTeam[r.Team.ID] = r
+	}
+
+	cmp := []XPtsComparison{}
+	for _, r := range rankings {
+		if row, ok := byTeam[r.Team.ID]; ok {
+			cmp = append(cmp, XPtsComparison{Ranking: r, XPts: row})
+		}
+	}
+	sort.SliceStable(cmp, func(i, j int) bool {
+		return cmp[i].Diff() > cmp[j].Diff()
+	})
+	return cmp
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fball
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BulkRequest is a single request to be run by Client.Bulk. Create one with NewBulkRequest.
+type BulkRequest struct {
+	call func(context.Context, *Client) (any, error)
+}
+
+// NewBulkRequest creates a BulkRequest that calls method with params. method is one of the
+// client endpoint methods, e.g.
+//
+//	fball.NewBulkRequest((*fball.Client).PlayerStats, fball.PlayerStatsParams{Fixture: "328362"})
+func NewBulkRequest[P, R any](method func(*Client, context.Context, P) (R, error), params P) BulkRequest {
+	return BulkRequest{
+		call: func(ctx context.Context, c *Client) (any, error) {
+			return method(c, ctx, params)
+		},
+	}
+}
+
+// BulkResult is the outcome of a BulkRequest. Value holds the response returned by the
+// endpoint method, e.g. an object.PlayerStatsResponse, and is nil if the request never ran.
+type BulkResult struct {
+	Value any
+	Err   error
+}
+
+// BulkValue returns the value of res as an R.
+func BulkValue[R any](res BulkResult) (R, error) {
+	v, ok := res.Value.(R)
+	if !ok && res.Value != nil {
+		return v, fmt.Errorf("invalid bulk value: expected %T, got %T", v, res.Value)
+	}
+	return v, res.Err
+}
+
+// Bulk runs reqs using at most workers concurrent requests. All requests share the client
+// rate limit. A failed request does not stop the others: the results are returned in the
+// same order as reqs, each with its own error. Once ctx is done, requests that have not
+// started fail with the ctx error, which is also returned by Bulk.
+func (c *Client) Bulk(ctx context.Context, workers int, reqs []BulkRequest) ([]BulkResult, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(reqs) {
+		workers = len(reqs)
+	}
+
+	results := make([]BulkResult, len(reqs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].Value, results[i].Err = reqs[i].call(ctx, c)
+			}
+		}()
+	}
+
+	for i := range reqs {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	return results, ctx.Err()
+}
@@ -19,12 +19,16 @@
 package fball
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -57,18 +61,62 @@ type Doer interface {
 
 // Client is an api-football.com client.
 type Client struct {
-	key  string
-	doer Doer
+	key       string
+	doer      Doer
+	observers []Observer
+	limit     *limiter
+	flights   flightGroup
+	keepRaw   bool
+	strict    bool
+}
+
+// Option configures optional behavior of a Client.
+type Option func(*Client)
+
+// WithObserver registers o to receive a Report for every request made by the client.
+func WithObserver(o Observer) Option {
+	return func(c *Client) {
+		c.observers = append(c.observers, o)
+	}
+}
+
+// WithRateLimit limits the client to at most n requests per period, spaced evenly. The limit
+// is shared by every request made through the client, including concurrent ones.
+func WithRateLimit(n int, per time.Duration) Option {
+	return func(c *Client) {
+		c.limit = newLimiter(n, per)
+	}
+}
+
+// WithRawJSON makes the client keep the raw json of every response, so fields not yet modeled
+// in the object package are not lost. See the RawEnvelope and RawResponse fields of the
+// responses. The raw json is also kept when the response fails to decode, e.g. because a field
+// changed type.
+func WithRawJSON() Option {
+	return func(c *Client) {
+		c.keepRaw = true
+	}
+}
+
+// WithStrictDecode makes requests fail with an ErrDecode error when a response has fields that
+// are not modeled in the object package. This is useful for detecting api changes in tests.
+func WithStrictDecode() Option {
+	return func(c *Client) {
+		c.strict = true
+	}
 }
 
 // NewClient creates an api-football.com client. The key is the one provided by the
-// service when you register it. limit will rate limit any request and if no logger
-// is provided, a default logger is used.
-func NewClient(key string, doer Doer) *Client {
-	return &Client{
+// service when you register it and doer is used to perform the http requests.
+func NewClient(key string, doer Doer, opts ...Option) *Client {
+	c := &Client{
 		doer: doer,
 		key:  key,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Response is an interface for api-football.com responses.
@@ -81,6 +129,12 @@ type Response interface {
 
 	// SetWhen sets the timestamp for the response.
 	SetWhen(int64)
+
+	// Count returns the number of results in the response.
+	Count() int
+
+	// Page returns the paging token for the response.
+	Page() object.PagingToken
 }
 
 const base = "https://v3.football.api-sports.io"
@@ -197,6 +251,7 @@ func (c *Client) Round(ctx context.Context, params RoundParams) (object.RoundRes
 
 type FixtureInfoParams struct {
 	ID       string
+	IDs      string
 	Live     string
 	Date     string
 	League   string
@@ -207,7 +262,7 @@ type FixtureInfoParams struct {
 	From     string
 	To       string
 	Round    string
-	Status   string
+	Status   object.FixtureStatus
 	Timezone string
 }
 
@@ -217,6 +272,79 @@ func (c *Client) FixtureInfo(ctx context.Context, params FixtureInfoParams) (obj
 	return fir, err
 }
 
+// maxFixtureIDs is the maximum number of ids accepted by the fixtures endpoint in one request.
+const maxFixtureIDs = 20
+
+// fixtureWorkers is the maximum number of concurrent requests made by FixturesByIDs.
+const fixtureWorkers = 4
+
+// FixturesByIDs fetches the fixtures with the given ids, including their events, lineups,
+// statistics and players. The ids are split in chunks the api accepts, which are fetched with
+// Bulk, at most 4 at a time and within the client rate limit. Fixtures are returned in the
+// order of ids, ids that are not found are skipped and repeated ids are returned once, at
+// their first position.
+func (c *Client) FixturesByIDs(ctx context.Context, ids []int) ([]object.FixtureDetail, error) {
+	seen := make(map[int]bool, len(ids))
+	chunks := [][]string{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if len(chunks) == 0 || len(chunks[len(chunks)-1]) == maxFixtureIDs {
+			chunks = append(chunks, make([]string, 0, maxFixtureIDs))
+		}
+		chunks[len(chunks)-1] = append(chunks[len(chunks)-1], strconv.Itoa(id))
+	}
+
+	// The first failed chunk cancels the others, since the fixtures are only returned if all of
+	// them are fetched.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reqs := make([]BulkRequest, len(chunks))
+	for i, chunk := range chunks {
+		params := FixtureInfoParams{IDs: strings.Join(chunk, "-")}
+		reqs[i] = BulkRequest{call: func(ctx context.Context, c *Client) (any, error) {
+			fir, err := c.FixtureInfo(ctx, params)
+			if err != nil {
+				cancel()
+			}
+			return fir, err
+		}}
+	}
+	results, _ := c.Bulk(ctx, fixtureWorkers, reqs)
+	for _, res := range results {
+		if res.Err != nil && !errors.Is(res.Err, context.Canceled) {
+			return nil, res.Err
+		}
+	}
+	for _, res := range results {
+		if res.Err != nil {
+			return nil, res.Err
+		}
+	}
+
+	byID := make(map[int]object.FixtureDetail, len(ids))
+	for _, res := range results {
+		fir, err := BulkValue[object.FixtureInfoResponse](res)
+		if err != nil {
+			return nil, err
+		}
+		for _, fixture := range fir.FixtureInfo {
+			byID[fixture.Fixture.ID] = fixture
+		}
+	}
+	fixtures := make([]object.FixtureDetail, 0, len(ids))
+	for _, id := range ids {
+		if fixture, ok := byID[id]; ok {
+			fixtures = append(fixtures, fixture)
+			delete(byID, id)
+		}
+	}
+	return fixtures, nil
+}
+
 type Head2HeadParams struct {
 	H2H      string
 	Date     string
@@ -226,7 +354,7 @@ type Head2HeadParams struct {
 	Next     string
 	From     string
 	To       string
-	Status   string
+	Status   object.FixtureStatus
 	Timezone string
 }
 
@@ -252,7 +380,7 @@ type EventParams struct {
 	Fixture string
 	Team    string
 	Player  string
-	Type    string
+	Type    object.EventType
 }
 
 func (c *Client) Event(ctx context.Context, params EventParams) (object.EventResponse, error) {
@@ -287,7 +415,7 @@ func (c *Client) PlayerStats(ctx context.Context, params PlayerStatsParams) (obj
 
 // Get will perform a GET request against the api-football service.
 // The response is returned in the data out param.
-func (c *Client) get(ctx context.Context, endpoint string, params any, data Response) error {
+func (c *Client) get(ctx context.Context, endpoint string, params any, data Response) (err error) {
 	if data == nil {
 		return fmt.Errorf("inalid data: must be non-nil")
 	}
@@ -296,32 +424,126 @@ func (c *Client) get(ctx context.Context, endpoint string, params any, data Resp
 	}
 
 	queryStr := toURLQueryString(params)
+	report := Report{
+		Endpoint: endpoint,
+		Query:    queryStr,
+		ErrKind:  ErrTransport,
+		Quota:    noQuota,
+	}
+	// Each StartObserver gets back the context it returned, so it can find what it stored
+	// there, e.g. its span. The request uses the context returned by the last one.
+	observerCtx := make([]context.Context, len(c.observers))
+	for i, o := range c.observers {
+		if so, ok := o.(StartObserver); ok {
+			ctx = so.Start(ctx, endpoint)
+			observerCtx[i] = ctx
+		}
+	}
+	start := time.Now()
+	defer func() {
+		report.Duration = time.Since(start)
+		report.Err = err
+		report.ErrKind = errorKind(ctx, err, report.ErrKind)
+		for i, o := range c.observers {
+			octx := observerCtx[i]
+			if octx == nil {
+				octx = ctx
+			}
+			o.Observe(octx, report)
+		}
+	}()
+
 	url := base + endpoint
 	if queryStr != "" {
 		url += "?"
 		url += queryStr
 	}
 
+	// Identical concurrent requests share a single http request. Each caller decodes its own
+	// copy of the response, so they never share data.
+	res, shared := c.flights.do(ctx, url, func() fetchResult {
+		return c.fetch(ctx, url)
+	})
+	report.CacheHit = shared
+	report.Quota = res.quota
+	if !shared {
+		report.Attempts = res.attempts
+	}
+	if res.err != nil {
+		return res.err
+	}
+
+	if err := c.decode(endpoint, res.body, data); err != nil {
+		report.ErrKind = ErrDecode
+		return err
+	}
+	data.SetWhen(res.when)
+	report.Results = data.Count()
+	report.Paging = data.Page()
+
+	report.ErrKind = ErrAPI
+	return data.Err()
+}
+
+// rawSetter is implemented by responses that can keep their raw json.
+type rawSetter interface {
+	SetRaw(envelope, response json.RawMessage)
+}
+
+func (c *Client) decode(endpoint string, body []byte, data Response) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if c.strict {
+		dec.DisallowUnknownFields()
+	}
+	err := dec.Decode(data)
+
+	if rs, ok := data.(rawSetter); ok && c.keepRaw {
+		envelope := struct {
+			Response json.RawMessage `json:"response"`
+		}{}
+		if envErr := json.Unmarshal(body, &envelope); envErr != nil {
+			if err == nil {
+				err = envErr
+			}
+		} else {
+			// body may be shared with other callers, so keep a copy of it.
+			rs.SetRaw(append(json.RawMessage(nil), body...), envelope.Response)
+		}
+	}
+
+	if err != nil && c.strict {
+		return fmt.Errorf("%s: %w", endpoint, err)
+	}
+	return err
+}
+
+// fetch performs a GET request for url and returns the raw response.
+func (c *Client) fetch(ctx context.Context, url string) fetchResult {
+	res := fetchResult{quota: noQuota}
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return err
+		res.err = err
+		return res
+	}
+
+	if err := c.limit.wait(ctx); err != nil {
+		res.err = err
+		return res
 	}
 
-	now := time.Now().UTC().UnixNano()
+	res.when = time.Now().UTC().UnixNano()
 	req.Header.Set("X-RapidAPI-Key", c.key)
+	res.attempts++
 	resp, err := c.doer.Do(req)
 	if err != nil {
-		return err
+		res.err = err
+		return res
 	}
 	defer resp.Body.Close()
+	res.quota = quotaFrom(resp.Header)
 
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(data); err != nil {
-		return err
-	}
-	data.SetWhen(now)
-
-	return data.Err()
+	res.body, res.err = io.ReadAll(resp.Body)
+	return res
 }
 
 func toURLQueryString(data any) string {
@@ -340,7 +562,7 @@ func toURLQueryString(data any) string {
 		if f.Kind() != reflect.String {
 			continue
 		}
-		val := f.Interface().(string)
+		val := f.String()
 		if val == "" {
 			continue
 		}
@@ -0,0 +1,181 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fball
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// doerFunc adapts a function to the Doer interface.
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// fixturesDoer answers fixture requests by id, leaving out the ids in missing.
+type fixturesDoer struct {
+	mu       sync.Mutex
+	requests [][]int
+	missing  map[int]bool
+	fail     map[int]error
+
+	// active is the number of requests in flight and maxActive the most seen at once.
+	active    int
+	maxActive int
+}
+
+func (d *fixturesDoer) Do(req *http.Request) (*http.Response, error) {
+	ids := []int{}
+	for _, s := range strings.Split(req.URL.Query().Get("ids"), "-") {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	d.mu.Lock()
+	d.requests = append(d.requests, ids)
+	d.active++
+	d.maxActive = max(d.maxActive, d.active)
+	d.mu.Unlock()
+	defer func() {
+		d.mu.Lock()
+		d.active--
+		d.mu.Unlock()
+	}()
+	time.Sleep(time.Millisecond)
+
+	fixtures := []string{}
+	for _, id := range ids {
+		if err := d.fail[id]; err != nil {
+			return nil, err
+		}
+		if !d.missing[id] {
+			fixtures = append(fixtures, fmt.Sprintf(`{"fixture": {"id": %d}}`, id))
+		}
+	}
+	return jsonResponse(fmt.Sprintf(`{"errors": [], "results": %d, "response": [%s]}`,
+		len(fixtures), strings.Join(fixtures, ","))), nil
+}
+
+func TestFixturesByIDs(t *testing.T) {
+	ids := []int{}
+	for id := 145; id > 100; id-- {
+		ids = append(ids, id)
+	}
+	ids = append(ids, 120, 145)
+
+	doer := &fixturesDoer{missing: map[int]bool{110: true}}
+	client := NewClient("key", doer)
+	fixtures, err := client.FixturesByIDs(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doer.requests) != 3 {
+		t.Errorf("got %d requests, want 3", len(doer.requests))
+	}
+	requested := map[int]int{}
+	for _, req := range doer.requests {
+		if len(req) > maxFixtureIDs {
+			t.Errorf("got %d ids in a request, want at most %d", len(req), maxFixtureIDs)
+		}
+		for _, id := range req {
+			requested[id]++
+		}
+	}
+	for id, n := range requested {
+		if n != 1 {
+			t.Errorf("id %d requested %d times, want 1", id, n)
+		}
+	}
+
+	want := []int{}
+	for id := 145; id > 100; id-- {
+		if id != 110 {
+			want = append(want, id)
+		}
+	}
+	got := make([]int, len(fixtures))
+	for i, f := range fixtures {
+		got[i] = f.Fixture.ID
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got fixtures %v, want %v", got, want)
+	}
+}
+
+func TestFixturesByIDsWorkers(t *testing.T) {
+	ids := []int{}
+	for id := 1; id <= 10*maxFixtureIDs; id++ {
+		ids = append(ids, id)
+	}
+
+	doer := &fixturesDoer{}
+	client := NewClient("key", doer)
+	fixtures, err := client.FixturesByIDs(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != len(ids) {
+		t.Errorf("got %d fixtures, want %d", len(fixtures), len(ids))
+	}
+	if doer.maxActive > fixtureWorkers {
+		t.Errorf("got %d concurrent requests, want at most %d", doer.maxActive, fixtureWorkers)
+	}
+}
+
+func TestFixturesByIDsError(t *testing.T) {
+	ids := []int{}
+	for id := 1; id <= 50; id++ {
+		ids = append(ids, id)
+	}
+	fail := errors.New("connection reset")
+
+	doer := &fixturesDoer{fail: map[int]error{42: fail}}
+	client := NewClient("key", doer)
+	if _, err := client.FixturesByIDs(context.Background(), ids); !errors.Is(err, fail) {
+		t.Errorf("got error %v, want %v", err, fail)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.FixturesByIDs(ctx, ids); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
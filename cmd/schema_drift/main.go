@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Command schema_drift reports differences between api-football.com payloads and the object
+// package structs. With no arguments it fetches a sample from every endpoint. Otherwise each
+// argument is an endpoint=file pair, e.g. /fixtures=testdata/fixtures.json, and the saved
+// payloads are checked instead. It exits with status 1 if anything was found.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/avalonbits/fball"
+	"github.com/avalonbits/fball/drift"
+)
+
+var (
+	key     = flag.String("key", "", "API key for football-api.")
+	league  = flag.String("league", drift.DefaultSample.League, "League id used for the samples.")
+	season  = flag.String("season", drift.DefaultSample.Season, "Season used for the samples.")
+	team    = flag.String("team", drift.DefaultSample.Team, "Team id used for the samples.")
+	fixture = flag.String("fixture", drift.DefaultSample.Fixture, "Fixture id used for the samples.")
+	h2h     = flag.String("h2h", drift.DefaultSample.H2H, "Head to head team ids used for the samples.")
+	country = flag.String("country", drift.DefaultSample.Country, "Country used for the samples.")
+)
+
+func main() {
+	flag.Parse()
+
+	var findings map[string][]drift.Finding
+	var errs map[string]error
+	if flag.NArg() == 0 {
+		c := fball.NewClient(*key, &http.Client{Timeout: 10 * time.Second}, fball.WithRawJSON())
+		findings, errs = drift.Check(context.Background(), c, drift.Sample{
+			League:  *league,
+			Season:  *season,
+			Team:    *team,
+			Fixture: *fixture,
+			H2H:     *h2h,
+			Country: *country,
+		})
+	} else {
+		findings, errs = checkFiles(flag.Args())
+	}
+
+	paths := []string{}
+	for path := range findings {
+		paths = append(paths, path)
+	}
+	for path := range errs {
+		if _, ok := findings[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	failed := false
+	for _, path := range paths {
+		if err, ok := errs[path]; ok {
+			fmt.Printf("%s: error: %v\n", path, err)
+			failed = true
+		}
+		for _, f := range findings[path] {
+			fmt.Printf("%s: %s\n", path, f)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func checkFiles(args []string) (map[string][]drift.Finding, map[string]error) {
+	samples := map[string][][]byte{}
+	errs := map[string]error{}
+	for _, arg := range args {
+		path, file, ok := strings.Cut(arg, "=")
+		if !ok {
+			fmt.Fprintf(os.Stderr, "invalid argument %q: expected endpoint=file\n", arg)
+			os.Exit(2)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errs[path] = err
+			continue
+		}
+		samples[path] = append(samples[path], data)
+	}
+
+	findings := map[string][]drift.Finding{}
+	for path, data := range samples {
+		ep, ok := drift.FindEndpoint(path)
+		if !ok {
+			errs[path] = fmt.Errorf("unknown endpoint")
+			continue
+		}
+		f, err := drift.Compare(ep.Type, data...)
+		if err != nil {
+			errs[path] = err
+			continue
+		}
+		findings[path] = f
+	}
+	return findings, errs
+}
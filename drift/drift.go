@@ -0,0 +1,269 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package drift compares api-football.com json payloads with the structs of the object
+// package and reports where they diverge.
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of divergence between a json payload and a struct.
+type Kind string
+
+const (
+	// Missing is a json field that has no matching struct field.
+	Missing Kind = "missing"
+	// Mismatch is a json value whose type can't be decoded into the struct field.
+	Mismatch Kind = "mismatch"
+	// AlwaysNull is a struct field that was null in every sample.
+	AlwaysNull Kind = "always-null"
+)
+
+// Finding is a divergence found at Path, e.g. "response[].fixture.venue.id".
+type Finding struct {
+	Path     string
+	Kind     Kind
+	JSONType string
+	GoType   string
+}
+
+func (f Finding) String() string {
+	switch f.Kind {
+	case Missing:
+		return fmt.Sprintf("%s: %s json field is missing from the struct", f.Path, f.JSONType)
+	case Mismatch:
+		return fmt.Sprintf("%s: json %s can't be decoded into %s", f.Path, f.JSONType, f.GoType)
+	}
+	return fmt.Sprintf("%s: %s is always null", f.Path, f.GoType)
+}
+
+// Compare walks the json samples against the type t, usually one of the object response
+// types, and returns the findings sorted by path.
+func Compare(t reflect.Type, samples ...[]byte) ([]Finding, error) {
+	w := &walker{fields: map[string]*fieldStats{}}
+	for i, sample := range samples {
+		var v interface{}
+		if err := json.Unmarshal(sample, &v); err != nil {
+			return nil, fmt.Errorf("invalid sample %d: %w", i, err)
+		}
+		w.walk("", v, t)
+	}
+	return w.findings(), nil
+}
+
+type fieldStats struct {
+	goType     string
+	seen       int
+	nulls      int
+	missing    map[string]bool
+	mismatches map[string]bool
+}
+
+type walker struct {
+	fields map[string]*fieldStats
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func (w *walker) stats(path string, t reflect.Type) *fieldStats {
+	fs, ok := w.fields[path]
+	if !ok {
+		fs = &fieldStats{
+			missing:    map[string]bool{},
+			mismatches: map[string]bool{},
+		}
+		if t != nil {
+			fs.goType = t.String()
+		}
+		w.fields[path] = fs
+	}
+	return fs
+}
+
+func (w *walker) walk(path string, v interface{}, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fs := w.stats(path, t)
+	fs.seen++
+	if v == nil {
+		fs.nulls++
+		return
+	}
+
+	// Types with their own decoding and interfaces accept any json value.
+	if t.Kind() == reflect.Interface || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := structFields(t)
+			for k, fv := range val {
+				ft, ok := lookup(fields, k)
+				if !ok {
+					w.stats(join(path, k), nil).missing[jsonType(fv)] = true
+					continue
+				}
+				w.walk(join(path, k), fv, ft)
+			}
+		case reflect.Map:
+			for _, fv := range val {
+				w.walk(join(path, "*"), fv, t.Elem())
+			}
+		default:
+			fs.mismatches[jsonType(v)] = true
+		}
+
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			fs.mismatches[jsonType(v)] = true
+			return
+		}
+		for _, e := range val {
+			w.walk(path+"[]", e, t.Elem())
+		}
+
+	case string:
+		if t.Kind() != reflect.String {
+			fs.mismatches[jsonType(v)] = true
+		}
+
+	case bool:
+		if t.Kind() != reflect.Bool {
+			fs.mismatches[jsonType(v)] = true
+		}
+
+	case float64:
+		switch t.Kind() {
+		case reflect.Float32, reflect.Float64:
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if val != float64(int64(val)) {
+				fs.mismatches["fractional number"] = true
+			}
+		default:
+			fs.mismatches[jsonType(v)] = true
+		}
+	}
+}
+
+func (w *walker) findings() []Finding {
+	findings := []Finding{}
+	for path, fs := range w.fields {
+		for jt := range fs.missing {
+			findings = append(findings, Finding{Path: path, Kind: Missing, JSONType: jt})
+		}
+		for jt := range fs.mismatches {
+			findings = append(findings, Finding{Path: path, Kind: Mismatch, JSONType: jt, GoType: fs.goType})
+		}
+		if fs.goType != "" && fs.seen > 0 && fs.nulls == fs.seen {
+			findings = append(findings, Finding{Path: path, Kind: AlwaysNull, JSONType: "null", GoType: fs.goType})
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		if findings[i].Kind != findings[j].Kind {
+			return findings[i].Kind < findings[j].Kind
+		}
+		return findings[i].JSONType < findings[j].JSONType
+	})
+	return findings
+}
+
+// structFields returns the json field names of t, including the ones promoted from embedded
+// structs, the same way encoding/json sees them.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for k, v := range structFields(ft) {
+				if _, ok := fields[k]; !ok {
+					fields[k] = v
+				}
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func lookup(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if t, ok := fields[key]; ok {
+		return t, true
+	}
+	for name, t := range fields {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/avalonbits/fball"
+	"github.com/avalonbits/fball/object"
+)
+
+// Sample are the parameters used to fetch a sample from each endpoint.
+type Sample struct {
+	League  string
+	Season  string
+	Team    string
+	Fixture string
+	H2H     string
+	Country string
+}
+
+// DefaultSample fetches data from the 2020 Brazilian league.
+var DefaultSample = Sample{
+	League:  "71",
+	Season:  "2020",
+	Team:    "123",
+	Fixture: "328362",
+	H2H:     "147-144",
+	Country: "Brazil",
+}
+
+// Endpoint is an api endpoint and the object type its responses are decoded into.
+type Endpoint struct {
+	Path string
+	Type reflect.Type
+
+	fetch func(context.Context, *fball.Client, Sample) (json.RawMessage, error)
+}
+
+// raw wraps a client method so that it returns the raw json of the response. A response that
+// does not decode because a value changed type is still returned, so Compare can report it
+// along with everything else.
+func raw[P any, R interface{ Raw() json.RawMessage }](
+	method func(*fball.Client, context.Context, P) (R, error), params func(Sample) P,
+) func(context.Context, *fball.Client, Sample) (json.RawMessage, error) {
+	return func(ctx context.Context, c *fball.Client, s Sample) (json.RawMessage, error) {
+		r, err := method(c, ctx, params(s))
+		var typeErr *json.UnmarshalTypeError
+		if err != nil && (!errors.As(err, &typeErr) || r.Raw() == nil) {
+			return nil, err
+		}
+		if r.Raw() == nil {
+			return nil, fmt.Errorf("no raw json: the client must be created with fball.WithRawJSON")
+		}
+		return r.Raw(), nil
+	}
+}
+
+func noParams(Sample) struct{} {
+	return struct{}{}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Endpoints are all the endpoints supported by fball.Client.
+var Endpoints = []Endpoint{
+	{
+		Path: "/timezone",
+		Type: typeOf[object.TimezoneResponse](),
+		fetch: raw(func(c *fball.Client, ctx context.Context, _ struct{}) (object.TimezoneResponse, error) {
+			return c.Timezone(ctx)
+		}, noParams),
+	},
+	{
+		Path: "/countries",
+		Type: typeOf[object.CountryResponse](),
+		fetch: raw((*fball.Client).Country, func(Sample) fball.CountryParams {
+			return fball.CountryParams{}
+		}),
+	},
+	{
+		Path: "/leagues/seasons",
+		Type: typeOf[object.SeasonResponse](),
+		fetch: raw(func(c *fball.Client, ctx context.Context, _ struct{}) (object.SeasonResponse, error) {
+			return c.Season(ctx)
+		}, noParams),
+	},
+	{
+		Path: "/leagues",
+		Type: typeOf[object.LeagueInfoResponse](),
+		fetch: raw((*fball.Client).LeagueInfo, func(s Sample) fball.LeagueInfoParams {
+			return fball.LeagueInfoParams{ID: s.League}
+		}),
+	},
+	{
+		Path: "/teams",
+		Type: typeOf[object.TeamInfoResponse](),
+		fetch: raw((*fball.Client).TeamInfo, func(s Sample) fball.TeamInfoParams {
+			return fball.TeamInfoParams{Country: s.Country}
+		}),
+	},
+	{
+		Path: "/teams/statistics",
+		Type: typeOf[object.TeamStatsResponse](),
+		fetch: raw((*fball.Client).TeamStats, func(s Sample) fball.TeamStatsParams {
+			return fball.TeamStatsParams{League: s.League, Season: s.Season, Team: s.Team}
+		}),
+	},
+	{
+		Path: "/venues",
+		Type: typeOf[object.VenueResponse](),
+		fetch: raw((*fball.Client).Venue, func(s Sample) fball.VenueParams {
+			return fball.VenueParams{Country: s.Country}
+		}),
+	},
+	{
+		Path: "/standings",
+		Type: typeOf[object.StandingsResponse](),
+		fetch: raw((*fball.Client).Standings, func(s Sample) fball.StandingsParams {
+			return fball.StandingsParams{League: s.League, Season: s.Season}
+		}),
+	},
+	{
+		Path: "/fixtures/rounds",
+		Type: typeOf[object.RoundResponse](),
+		fetch: raw((*fball.Client).Round, func(s Sample) fball.RoundParams {
+			return fball.RoundParams{League: s.League, Season: s.Season}
+		}),
+	},
+	{
+		Path: "/fixtures",
+		Type: typeOf[object.FixtureInfoResponse](),
+		fetch: raw((*fball.Client).FixtureInfo, func(s Sample) fball.FixtureInfoParams {
+			return fball.FixtureInfoParams{ID: s.Fixture}
+		}),
+	},
+	{
+		Path: "/fixtures/headtohead",
+		Type: typeOf[object.Head2HeadResponse](),
+		fetch: raw((*fball.Client).Head2Head, func(s Sample) fball.Head2HeadParams {
+			return fball.Head2HeadParams{H2H: s.H2H, League: s.League, Season: s.Season}
+		}),
+	},
+	{
+		Path: "/fixtures/statistics",
+		Type: typeOf[object.FixtureStatsResponse](),
+		fetch: raw((*fball.Client).FixtureStats, func(s Sample) fball.FixtureStatsParams {
+			return fball.FixtureStatsParams{Fixture: s.Fixture}
+		}),
+	},
+	{
+		Path: "/fixtures/events",
+		Type: typeOf[object.EventResponse](),
+		fetch: raw((*fball.Client).Event, func(s Sample) fball.EventParams {
+			return fball.EventParams{Fixture: s.Fixture}
+		}),
+	},
+	{
+		Path: "/fixtures/lineups",
+		Type: typeOf[object.LineupResponse](),
+		fetch: raw((*fball.Client).Lineup, func(s Sample) fball.LineupParams {
+			return fball.LineupParams{Fixture: s.Fixture}
+		}),
+	},
+	{
+		Path: "/fixtures/players",
+		Type: typeOf[object.PlayerStatsResponse](),
+		fetch: raw((*fball.Client).PlayerStats, func(s Sample) fball.PlayerStatsParams {
+			return fball.PlayerStatsParams{Fixture: s.Fixture}
+		}),
+	},
+}
+
+// FindEndpoint returns the endpoint with the given path.
+func FindEndpoint(path string) (Endpoint, bool) {
+	for _, ep := range Endpoints {
+		if ep.Path == path {
+			return ep, true
+		}
+	}
+	return Endpoint{}, false
+}
+
+// Fetch gets a sample from the endpoint. The client must be created with fball.WithRawJSON.
+func (ep Endpoint) Fetch(ctx context.Context, c *fball.Client, s Sample) (json.RawMessage, error) {
+	return ep.fetch(ctx, c, s)
+}
+
+// Check fetches a sample from each endpoint and compares it with its object type. Endpoints
+// that fail to fetch are reported in errs.
+func Check(ctx context.Context, c *fball.Client, s Sample) (findings map[string][]Finding, errs map[string]error) {
+	findings = map[string][]Finding{}
+	errs = map[string]error{}
+	for _, ep := range Endpoints {
+		sample, err := ep.Fetch(ctx, c, s)
+		if err == nil {
+			findings[ep.Path], err = Compare(ep.Type, sample)
+		}
+		if err != nil {
+			errs[ep.Path] = err
+		}
+	}
+	return findings, errs
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package drift
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/avalonbits/fball"
+)
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonDoer(body string) fball.Doer {
+	return doerFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+}
+
+func TestFetchTypeMismatch(t *testing.T) {
+	const body = `{
+		"get": "fixtures",
+		"parameters": {"id": "328362"},
+		"errors": [],
+		"results": 1,
+		"paging": {"current": 1, "total": 1},
+		"response": [{"fixture": {"id": "abc", "newfield": 1}}]
+	}`
+	c := fball.NewClient("key", jsonDoer(body), fball.WithRawJSON())
+	ep, ok := FindEndpoint("/fixtures")
+	if !ok {
+		t.Fatal("endpoint /fixtures not found")
+	}
+
+	sample, err := ep.Fetch(context.Background(), c, DefaultSample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := Compare(ep.Type, sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]Finding{
+		"response[].fixture.id":       {Path: "response[].fixture.id", Kind: Mismatch, JSONType: "string", GoType: "int"},
+		"response[].fixture.newfield": {Path: "response[].fixture.newfield", Kind: Missing, JSONType: "number"},
+	}
+	for _, f := range findings {
+		if w, ok := want[f.Path]; ok && f == w {
+			delete(want, f.Path)
+		}
+	}
+	for _, w := range want {
+		t.Errorf("finding %q not reported, got %v", w, findings)
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	ep, _ := FindEndpoint("/fixtures")
+	tests := []struct {
+		name string
+		body string
+		opts []fball.Option
+	}{
+		{"without raw json", `{"errors": [], "response": []}`, nil},
+		{"api error", `{"errors": {"token": "invalid key"}, "response": []}`, []fball.Option{fball.WithRawJSON()}},
+		{"invalid json", `{"response": [`, []fball.Option{fball.WithRawJSON()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fball.NewClient("key", jsonDoer(tt.body), tt.opts...)
+			if sample, err := ep.Fetch(context.Background(), c, DefaultSample); err == nil {
+				t.Errorf("got sample %s, want an error", sample)
+			}
+		})
+	}
+}
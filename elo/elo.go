@@ -0,0 +1,220 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package elo rates teams with the Elo rating system from their fixture results.
+package elo
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/avalonbits/fball/object"
+)
+
+// Config configures the rating system.
+type Config struct {
+	// Initial is the rating of a team before its first fixture. It is also the mean ratings
+	// regress to between seasons.
+	Initial float64
+
+	// K is how much a single result moves the ratings.
+	K float64
+
+	// HomeAdvantage is added to the home team rating when computing the expected result.
+	HomeAdvantage float64
+
+	// GoalDifference scales K by the margin of victory: 1 for one goal, 1.5 for two and
+	// (11+n)/8 for n >= 3.
+	GoalDifference bool
+
+	// SeasonRegression is the fraction of the distance to Initial a rating loses when a team
+	// plays its first fixture of a new season, from 0 to 1.
+	SeasonRegression float64
+
+	// DrawRate is the probability of a draw between evenly matched teams. It is used to split
+	// the expected result into win, draw and loss probabilities.
+	DrawRate float64
+}
+
+// DefaultConfig returns a configuration that works well for most domestic leagues.
+func DefaultConfig() Config {
+	return Config{
+		Initial:          1500,
+		K:                20,
+		HomeAdvantage:    65,
+		GoalDifference:   true,
+		SeasonRegression: 1.0 / 3,
+		DrawRate:         0.27,
+	}
+}
+
+// Point is the rating of a team after a fixture.
+type Point struct {
+	FixtureID int
+	Time      time.Time
+	Rating    float64
+}
+
+// Probabilities are the chances of each result of a fixture.
+type Probabilities struct {
+	Home float64
+	Draw float64
+	Away float64
+}
+
+// Ratings keeps the ratings of every team seen so far.
+type Ratings struct {
+	cfg     Config
+	ratings map[int]float64
+	seasons map[int]int
+	history map[int][]Point
+}
+
+// New creates Ratings with cfg.
+func New(cfg Config) *Ratings {
+	return &Ratings{
+		cfg:     cfg,
+		ratings: map[int]float64{},
+		seasons: map[int]int{},
+		history: map[int][]Point{},
+	}
+}
+
+// Update rates the fixtures, in order of Fixture.Timestamp. Fixtures without a final result
+// are ignored.
+func (r *Ratings) Update(fixtures []object.FixtureDetail) {
+	sorted := append([]object.FixtureDetail(nil), fixtures...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Fixture.Timestamp < sorted[j].Fixture.Timestamp
+	})
+
+	for _, f := range sorted {
+		if !f.Fixture.Status.Short.IsFinished() {
+			continue
+		}
+		home := r.seasonRating(f.Teams.Home.ID, f.League.Season)
+		away := r.seasonRating(f.Teams.Away.ID, f.League.Season)
+
+		expected := r.expected(home, away)
+		actual := 0.5
+		switch {
+		case f.Goals.Home > f.Goals.Away:
+			actual = 1
+		case f.Goals.Home < f.Goals.Away:
+			actual = 0
+		}
+
+		k := r.cfg.K
+		if r.cfg.GoalDifference {
+			k *= marginMultiplier(f.Goals.Home - f.Goals.Away)
+		}
+		delta := k * (actual - expected)
+
+		when := f.Fixture.Kickoff()
+		r.set(f.Teams.Home.ID, f.Fixture.ID, when, home+delta)
+		r.set(f.Teams.Away.ID, f.Fixture.ID, when, away-delta)
+	}
+}
+
+// seasonRating returns the rating of team, first regressing it to the mean if season is a new
+// season for the team.
+func (r *Ratings) seasonRating(team, season int) float64 {
+	rating, ok := r.ratings[team]
+	if !ok {
+		rating = r.cfg.Initial
+	} else if last := r.seasons[team]; season > last {
+		rating -= (rating - r.cfg.Initial) * r.cfg.SeasonRegression
+	}
+	r.seasons[team] = season
+	return rating
+}
+
+func (r *Ratings) set(team, fixture int, when time.Time, rating float64) {
+	r.ratings[team] = rating
+	r.history[team] = append(r.history[team], Point{
+		FixtureID: fixture,
+		Time:      when,
+		Rating:    rating,
+	})
+}
+
+// expected returns the expected score of the home team, where a win is 1 and a draw 0.5.
+func (r *Ratings) expected(home, away float64) float64 {
+	return 1 / (1 + math.Pow(10, -(home+r.cfg.HomeAdvantage-away)/400))
+}
+
+func marginMultiplier(goalDiff int) float64 {
+	if goalDiff < 0 {
+		goalDiff = -goalDiff
+	}
+	switch {
+	case goalDiff <= 1:
+		return 1
+	case goalDiff == 2:
+		return 1.5
+	}
+	return (11 + float64(goalDiff)) / 8
+}
+
+// Rating returns the current rating of team.
+func (r *Ratings) Rating(team int) float64 {
+	if rating, ok := r.ratings[team]; ok {
+		return rating
+	}
+	return r.cfg.Initial
+}
+
+// History returns the rating of team after each of its rated fixtures.
+func (r *Ratings) History(team int) []Point {
+	return append([]Point(nil), r.history[team]...)
+}
+
+// Teams returns the ids of all rated teams, sorted by rating from highest to lowest.
+func (r *Ratings) Teams() []int {
+	teams := make([]int, 0, len(r.ratings))
+	for team := range r.ratings {
+		teams = append(teams, team)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		if r.ratings[teams[i]] != r.ratings[teams[j]] {
+			return r.ratings[teams[i]] > r.ratings[teams[j]]
+		}
+		return teams[i] < teams[j]
+	})
+	return teams
+}
+
+// Predict returns the result probabilities of a fixture between home and away. The draw
+// probability is DrawRate for evenly matched teams and shrinks as the expected score moves
+// away from 0.5, keeping Home + Draw/2 equal to the expected score.
+func (r *Ratings) Predict(home, away int) Probabilities {
+	e := r.expected(r.Rating(home), r.Rating(away))
+	draw := r.cfg.DrawRate * (1 - math.Abs(2*e-1))
+	return Probabilities{
+		Home: e - draw/2,
+		Draw: draw,
+		Away: 1 - e - draw/2,
+	}
+}
+
+// PredictFixture returns the result probabilities of an upcoming fixture, e.g. one returned by
+// Client.FixtureInfo with the Next param.
+func (r *Ratings) PredictFixture(f object.FixtureDetail) Probabilities {
+	return r.Predict(f.Teams.Home.ID, f.Teams.Away.ID)
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fball
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// fetchResult is the raw outcome of an http request, before it is decoded.
+type fetchResult struct {
+	body     []byte
+	when     int64
+	quota    Quota
+	attempts int
+	err      error
+}
+
+type flight struct {
+	done chan struct{}
+	res  fetchResult
+
+	// dups is the number of callers that joined the flight instead of making the request.
+	dups int
+}
+
+// flightGroup collapses identical concurrent requests into a single http request. Callers
+// share the raw response body and each decodes its own copy of it.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// do calls fn, unless a call with the same key is in flight, in which case it waits for that
+// call and returns its result instead. shared reports whether the result came from another
+// caller.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() fetchResult) (res fetchResult, shared bool) {
+	for {
+		g.mu.Lock()
+		if g.flights == nil {
+			g.flights = map[string]*flight{}
+		}
+		if f, ok := g.flights[key]; ok {
+			f.dups++
+			g.mu.Unlock()
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return fetchResult{quota: noQuota, err: ctx.Err()}, false
+			}
+
+			// The caller that made the request gave up, but we didn't. Try again.
+			if isContextErr(f.res.err) {
+				continue
+			}
+			return f.res, true
+		}
+
+		f := &flight{done: make(chan struct{})}
+		g.flights[key] = f
+		g.mu.Unlock()
+
+		f.res = fn()
+
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+		close(f.done)
+
+		return f.res, false
+	}
+}
+
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fball
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avalonbits/fball/object"
+)
+
+const playerStatsBody = `{
+	"errors": [],
+	"results": 1,
+	"response": [{"team": {"id": 33, "name": "Manchester United"}}]
+}`
+
+// reportRecorder is an Observer that keeps every Report.
+type reportRecorder struct {
+	mu      sync.Mutex
+	reports []Report
+}
+
+func (r *reportRecorder) Observe(ctx context.Context, report Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+}
+
+// waitForDups waits until there is a request in flight and n callers joined it.
+func waitForDups(t *testing.T, c *Client, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		c.flights.mu.Lock()
+		dups := -1
+		for _, f := range c.flights.flights {
+			dups = f.dups
+		}
+		c.flights.mu.Unlock()
+		if dups == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d callers to join the flight", n)
+}
+
+func TestFlightSharesRequest(t *testing.T) {
+	const callers = 8
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		<-release
+		return jsonResponse(playerStatsBody), nil
+	})
+	rec := &reportRecorder{}
+	client := NewClient("key", doer, WithObserver(rec), WithRawJSON())
+
+	params := PlayerStatsParams{Fixture: "169080"}
+	responses := make([]object.PlayerStatsResponse, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = client.PlayerStats(context.Background(), params)
+		}(i)
+	}
+	waitForDups(t, client, callers-1)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("got %d http requests, want 1", n)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("caller %d: %v", i, err)
+		}
+	}
+
+	// Changing one response must not change the others.
+	responses[0].PlayerStats[0].Team.Name = "changed"
+	responses[0].RawEnvelope[0] = 'X'
+	for i, r := range responses[1:] {
+		if name := r.PlayerStats[0].Team.Name; name != "Manchester United" {
+			t.Errorf("caller %d: got team %q, want %q", i+1, name, "Manchester United")
+		}
+		if r.RawEnvelope[0] != '{' {
+			t.Errorf("caller %d: raw envelope shared with another caller", i+1)
+		}
+	}
+
+	hits, attempts := 0, 0
+	for _, r := range rec.reports {
+		if r.CacheHit {
+			hits++
+		}
+		attempts += r.Attempts
+	}
+	if len(rec.reports) != callers || hits != callers-1 || attempts != 1 {
+		t.Errorf("got %d reports with %d cache hits and %d attempts, want %d, %d and 1",
+			len(rec.reports), hits, attempts, callers, callers-1)
+	}
+}
+
+func TestFlightLeaderCanceled(t *testing.T) {
+	var calls atomic.Int32
+	doer := doerFunc(func(req *http.Request) (*http.Response, error) {
+		// The first request blocks until its caller gives up.
+		if calls.Add(1) == 1 {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		return jsonResponse(playerStatsBody), nil
+	})
+	rec := &reportRecorder{}
+	client := NewClient("key", doer, WithObserver(rec))
+	params := PlayerStatsParams{Fixture: "169080"}
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := client.PlayerStats(leaderCtx, params)
+		leaderErr <- err
+	}()
+	waitForDups(t, client, 0)
+
+	followerErr := make(chan error)
+	var follower object.PlayerStatsResponse
+	go func() {
+		var err error
+		follower, err = client.PlayerStats(context.Background(), params)
+		followerErr <- err
+	}()
+	waitForDups(t, client, 1)
+
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader: got error %v, want %v", err, context.Canceled)
+	}
+	if err := <-followerErr; err != nil {
+		t.Fatalf("follower: %v", err)
+	}
+	if len(follower.PlayerStats) != 1 {
+		t.Errorf("follower: got %d results, want 1", len(follower.PlayerStats))
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("got %d http requests, want 2", n)
+	}
+
+	for _, r := range rec.reports {
+		if r.CacheHit || r.Attempts != 1 {
+			t.Errorf("%s report: got cache hit %v and %d attempts, want false and 1",
+				r.ErrKind, r.CacheHit, r.Attempts)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics collects api usage and latency metrics from a fball.Client and renders
+// them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/avalonbits/fball"
+)
+
+// DefaultBuckets are the latency histogram upper bounds, in seconds, used by New.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics is a fball.Observer that keeps request counters, latency histograms and quota
+// gauges. It is safe for concurrent use.
+type Metrics struct {
+	mu        sync.Mutex
+	buckets   []float64
+	requests  map[string]uint64
+	errors    map[errorKey]uint64
+	cacheHits map[string]uint64
+	latency   map[string]*histogram
+
+	dailyRemaining  float64
+	minuteRemaining float64
+}
+
+type errorKey struct {
+	endpoint string
+	kind     fball.ErrorKind
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// New creates a Metrics. If no buckets are provided, DefaultBuckets is used.
+func New(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+
+	return &Metrics{
+		buckets:         b,
+		requests:        map[string]uint64{},
+		errors:          map[errorKey]uint64{},
+		cacheHits:       map[string]uint64{},
+		latency:         map[string]*histogram{},
+		dailyRemaining:  math.NaN(),
+		minuteRemaining: math.NaN(),
+	}
+}
+
+// Observe implements fball.Observer.
+func (m *Metrics) Observe(_ context.Context, r fball.Report) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[r.Endpoint]++
+	if r.Err != nil {
+		m.errors[errorKey{endpoint: r.Endpoint, kind: r.ErrKind}]++
+	}
+	if r.CacheHit {
+		m.cacheHits[r.Endpoint]++
+	}
+
+	h, ok := m.latency[r.Endpoint]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latency[r.Endpoint] = h
+	}
+	secs := r.Duration.Seconds()
+	for i, le := range m.buckets {
+		if secs <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += secs
+
+	if r.Quota.DailyRemaining >= 0 {
+		m.dailyRemaining = float64(r.Quota.DailyRemaining)
+	}
+	if r.Quota.MinuteRemaining >= 0 {
+		m.minuteRemaining = float64(r.Quota.MinuteRemaining)
+	}
+}
+
+// snapshot is a copy of the metrics, so they can be rendered without holding the lock.
+type snapshot struct {
+	buckets   []float64
+	requests  map[string]uint64
+	errors    map[errorKey]uint64
+	cacheHits map[string]uint64
+	latency   map[string]histogram
+
+	dailyRemaining  float64
+	minuteRemaining float64
+}
+
+func (m *Metrics) snapshot() snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := snapshot{
+		buckets:         m.buckets,
+		requests:        make(map[string]uint64, len(m.requests)),
+		errors:          make(map[errorKey]uint64, len(m.errors)),
+		cacheHits:       make(map[string]uint64, len(m.cacheHits)),
+		latency:         make(map[string]histogram, len(m.latency)),
+		dailyRemaining:  m.dailyRemaining,
+		minuteRemaining: m.minuteRemaining,
+	}
+	for k, v := range m.requests {
+		s.requests[k] = v
+	}
+	for k, v := range m.errors {
+		s.errors[k] = v
+	}
+	for k, v := range m.cacheHits {
+		s.cacheHits[k] = v
+	}
+	for k, h := range m.latency {
+		s.latency[k] = histogram{counts: append([]uint64(nil), h.counts...), count: h.count, sum: h.sum}
+	}
+	return s
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	s := m.snapshot()
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	cw.header("fball_requests_total", "counter", "Requests made to api-football.com.")
+	for _, ep := range sortedKeys(s.requests) {
+		cw.printf("fball_requests_total{endpoint=%q} %d\n", ep, s.requests[ep])
+	}
+
+	cw.header("fball_errors_total", "counter", "Failed requests by error kind.")
+	errKeys := make([]errorKey, 0, len(s.errors))
+	for k := range s.errors {
+		errKeys = append(errKeys, k)
+	}
+	sort.Slice(errKeys, func(i, j int) bool {
+		if errKeys[i].endpoint != errKeys[j].endpoint {
+			return errKeys[i].endpoint < errKeys[j].endpoint
+		}
+		return errKeys[i].kind < errKeys[j].kind
+	})
+	for _, k := range errKeys {
+		cw.printf("fball_errors_total{endpoint=%q,kind=%q} %d\n", k.endpoint, k.kind, s.errors[k])
+	}
+
+	cw.header("fball_cache_hits_total", "counter", "Requests served without an http call.")
+	for _, ep := range sortedKeys(s.cacheHits) {
+		cw.printf("fball_cache_hits_total{endpoint=%q} %d\n", ep, s.cacheHits[ep])
+	}
+
+	cw.header("fball_request_duration_seconds", "histogram", "Request latency.")
+	for _, ep := range sortedKeys(s.latency) {
+		h := s.latency[ep]
+		for i, le := range s.buckets {
+			cw.printf("fball_request_duration_seconds_bucket{endpoint=%q,le=%q} %d\n", ep, formatFloat(le), h.counts[i])
+		}
+		cw.printf("fball_request_duration_seconds_bucket{endpoint=%q,le=\"+Inf\"} %d\n", ep, h.count)
+		cw.printf("fball_request_duration_seconds_sum{endpoint=%q} %s\n", ep, formatFloat(h.sum))
+		cw.printf("fball_request_duration_seconds_count{endpoint=%q} %d\n", ep, h.count)
+	}
+
+	cw.header("fball_quota_daily_remaining", "gauge", "Requests left in the daily quota.")
+	cw.printf("fball_quota_daily_remaining %s\n", formatFloat(s.dailyRemaining))
+	cw.header("fball_quota_minute_remaining", "gauge", "Requests left in the per-minute quota.")
+	cw.printf("fball_quota_minute_remaining %s\n", formatFloat(s.minuteRemaining))
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// Handler returns an http.Handler that serves the metrics in the Prometheus text exposition
+// format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WriteTo(w)
+	})
+}
+
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) printf(format string, args ...any) {
+	if cw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+func (cw *countWriter) header(name, typ, help string) {
+	cw.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avalonbits/fball"
+)
+
+const emptyGolden = `# HELP fball_requests_total Requests made to api-football.com.
+# TYPE fball_requests_total counter
+# HELP fball_errors_total Failed requests by error kind.
+# TYPE fball_errors_total counter
+# HELP fball_cache_hits_total Requests served without an http call.
+# TYPE fball_cache_hits_total counter
+# HELP fball_request_duration_seconds Request latency.
+# TYPE fball_request_duration_seconds histogram
+# HELP fball_quota_daily_remaining Requests left in the daily quota.
+# TYPE fball_quota_daily_remaining gauge
+fball_quota_daily_remaining NaN
+# HELP fball_quota_minute_remaining Requests left in the per-minute quota.
+# TYPE fball_quota_minute_remaining gauge
+fball_quota_minute_remaining NaN
+`
+
+const observedGolden = `# HELP fball_requests_total Requests made to api-football.com.
+# TYPE fball_requests_total counter
+fball_requests_total{endpoint="fixtures"} 2
+fball_requests_total{endpoint="players"} 1
+# HELP fball_errors_total Failed requests by error kind.
+# TYPE fball_errors_total counter
+fball_errors_total{endpoint="fixtures",kind="api"} 1
+fball_errors_total{endpoint="players",kind="transport"} 1
+# HELP fball_cache_hits_total Requests served without an http call.
+# TYPE fball_cache_hits_total counter
+fball_cache_hits_total{endpoint="fixtures"} 1
+# HELP fball_request_duration_seconds Request latency.
+# TYPE fball_request_duration_seconds histogram
+fball_request_duration_seconds_bucket{endpoint="fixtures",le="0.1"} 1
+fball_request_duration_seconds_bucket{endpoint="fixtures",le="1"} 2
+fball_request_duration_seconds_bucket{endpoint="fixtures",le="+Inf"} 2
+fball_request_duration_seconds_sum{endpoint="fixtures"} 0.75
+fball_request_duration_seconds_count{endpoint="fixtures"} 2
+fball_request_duration_seconds_bucket{endpoint="players",le="0.1"} 0
+fball_request_duration_seconds_bucket{endpoint="players",le="1"} 0
+fball_request_duration_seconds_bucket{endpoint="players",le="+Inf"} 1
+fball_request_duration_seconds_sum{endpoint="players"} 2
+fball_request_duration_seconds_count{endpoint="players"} 1
+# HELP fball_quota_daily_remaining Requests left in the daily quota.
+# TYPE fball_quota_daily_remaining gauge
+fball_quota_daily_remaining 90
+# HELP fball_quota_minute_remaining Requests left in the per-minute quota.
+# TYPE fball_quota_minute_remaining gauge
+fball_quota_minute_remaining 5
+`
+
+func TestWriteTo(t *testing.T) {
+	noQuota := fball.Quota{DailyLimit: -1, DailyRemaining: -1, MinuteLimit: -1, MinuteRemaining: -1}
+	reports := []fball.Report{
+		{Endpoint: "fixtures", Duration: 50 * time.Millisecond, CacheHit: true, Quota: noQuota},
+		{
+			Endpoint: "fixtures",
+			Duration: 700 * time.Millisecond,
+			Err:      errors.New("api error"),
+			ErrKind:  fball.ErrAPI,
+			Quota:    fball.Quota{DailyLimit: 100, DailyRemaining: 90, MinuteLimit: 10, MinuteRemaining: 5},
+		},
+		// Reports without a quota keep the last one seen.
+		{
+			Endpoint: "players",
+			Duration: 2 * time.Second,
+			Err:      errors.New("connection reset"),
+			ErrKind:  fball.ErrTransport,
+			Quota:    noQuota,
+		},
+	}
+
+	m := New(1, 0.1)
+	sb := &strings.Builder{}
+	if _, err := m.WriteTo(sb); err != nil {
+		t.Fatal(err)
+	}
+	if got := sb.String(); got != emptyGolden {
+		t.Errorf("before any report got:\n%s\nwant:\n%s", got, emptyGolden)
+	}
+
+	for _, r := range reports {
+		m.Observe(context.Background(), r)
+	}
+	sb.Reset()
+	n, err := m.WriteTo(sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sb.String(); got != observedGolden {
+		t.Errorf("got:\n%s\nwant:\n%s", got, observedGolden)
+	}
+	if n != int64(sb.Len()) {
+		t.Errorf("got %d bytes written, want %d", n, sb.Len())
+	}
+}
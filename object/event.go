@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import (
+	"sort"
+	"strings"
+)
+
+// EventType is the kind of a fixture event. It is also accepted as the Type filter of the
+// events endpoint.
+type EventType string
+
+const (
+	EventGoal  EventType = "Goal"
+	EventCard  EventType = "Card"
+	EventSubst EventType = "subst"
+	EventVar   EventType = "Var"
+)
+
+// EventDetail describes a fixture event in more detail than its type.
+type EventDetail string
+
+const (
+	DetailNormalGoal     EventDetail = "Normal Goal"
+	DetailOwnGoal        EventDetail = "Own Goal"
+	DetailPenalty        EventDetail = "Penalty"
+	DetailMissedPenalty  EventDetail = "Missed Penalty"
+	DetailYellowCard     EventDetail = "Yellow Card"
+	DetailSecondYellow   EventDetail = "Second Yellow card"
+	DetailRedCard        EventDetail = "Red Card"
+	DetailGoalCancelled  EventDetail = "Goal cancelled"
+	DetailPenaltyConfirm EventDetail = "Penalty confirmed"
+
+	// Substitutions are numbered, e.g. "Substitution 1".
+	DetailSubstitution EventDetail = "Substitution"
+)
+
+func (t EventType) is(o EventType) bool {
+	return strings.EqualFold(string(t), string(o))
+}
+
+func (d EventDetail) is(o EventDetail) bool {
+	return strings.EqualFold(string(d), string(o))
+}
+
+// IsGoal returns true if the event is a goal that counts for the score, including own goals
+// and penalties. Missed penalties are not goals.
+func (e Event) IsGoal() bool {
+	return e.Type.is(EventGoal) && !e.Detail.is(DetailMissedPenalty)
+}
+
+// IsOwnGoal returns true if the event is an own goal. The event team is the one that
+// benefited from it.
+func (e Event) IsOwnGoal() bool {
+	return e.Type.is(EventGoal) && e.Detail.is(DetailOwnGoal)
+}
+
+// IsPenaltyGoal returns true if the event is a goal scored from a penalty in regular or extra
+// time.
+func (e Event) IsPenaltyGoal() bool {
+	return e.Type.is(EventGoal) && e.Detail.is(DetailPenalty)
+}
+
+// IsMissedPenalty returns true if the event is a missed penalty.
+func (e Event) IsMissedPenalty() bool {
+	return e.Type.is(EventGoal) && e.Detail.is(DetailMissedPenalty)
+}
+
+// IsCard returns true if the event is a yellow or red card.
+func (e Event) IsCard() bool {
+	return e.Type.is(EventCard)
+}
+
+// IsYellowCard returns true if the event is a first yellow card.
+func (e Event) IsYellowCard() bool {
+	return e.IsCard() && e.Detail.is(DetailYellowCard)
+}
+
+// IsSecondYellow returns true if the event is a second yellow card, which also sends the
+// player off.
+func (e Event) IsSecondYellow() bool {
+	return e.IsCard() && e.Detail.is(DetailSecondYellow)
+}
+
+// IsRedCard returns true if the event sent a player off, either by a straight red or a
+// second yellow card.
+func (e Event) IsRedCard() bool {
+	return e.IsCard() && (e.Detail.is(DetailRedCard) || e.Detail.is(DetailSecondYellow))
+}
+
+// IsSubstitution returns true if the event is a substitution. Player and Assist are the two
+// players involved in it.
+func (e Event) IsSubstitution() bool {
+	return e.Type.is(EventSubst)
+}
+
+// IsVARDecision returns true if the event is a VAR review decision.
+func (e Event) IsVARDecision() bool {
+	return e.Type.is(EventVar)
+}
+
+// Minute returns the minute of the event, including the time added at the end of a period.
+func (e Event) Minute() int {
+	return e.Time.Elapsed + e.Time.Extra
+}
+
+// CompareEvents orders events by elapsed time and then by time added at the end of the
+// period, so 45+2 comes before 46 and 90+5 before 91. It returns -1, 0 or 1.
+func CompareEvents(a, b Event) int {
+	switch {
+	case a.Time.Elapsed < b.Time.Elapsed:
+		return -1
+	case a.Time.Elapsed > b.Time.Elapsed:
+		return 1
+	case a.Time.Extra < b.Time.Extra:
+		return -1
+	case a.Time.Extra > b.Time.Extra:
+		return 1
+	}
+	return 0
+}
+
+// SortEvents sorts events in the order of CompareEvents. Events at the same time keep the
+// order sent by the api.
+func SortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return CompareEvents(events[i], events[j]) < 0
+	})
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+// MinuteBucket is the number of occurrences of something, e.g. goals or cards, between the From
+// and To minutes of a game, inclusive. Percentage is the share of the bucket in its histogram,
+// from 0 to 100.
+type MinuteBucket struct {
+	From       int
+	To         int
+	Count      int
+	Percentage NullFloat
+}
+
+// Buckets returns the game time histogram as buckets ordered by minute. Percentages are the
+// ones sent by the api.
+func (g GameTime) Buckets() []MinuteBucket {
+	periods := []struct {
+		from, to int
+		tp       TotalPercent
+	}{
+		{0, 15, g.P15},
+		{16, 30, g.P30},
+		{31, 45, g.P45},
+		{46, 60, g.P60},
+		{61, 75, g.P75},
+		{76, 90, g.P90},
+		{91, 105, g.P105},
+		{106, 120, g.P120},
+	}
+
+	buckets := make([]MinuteBucket, len(periods))
+	for i, p := range periods {
+		pct, valid, err := toNullNumber(p.tp.Percentage)
+		if p.tp.Percentage == "" || err != nil {
+			valid = false
+		}
+		buckets[i] = MinuteBucket{
+			From:       p.from,
+			To:         p.to,
+			Count:      p.tp.Total,
+			Percentage: NullFloat{Value: pct, Valid: valid},
+		}
+	}
+	return buckets
+}
+
+// MergeBuckets adds the counts of buckets with the same bounds, e.g. the goals of a team across
+// seasons, and recomputes the percentages from the merged counts. Buckets are returned in the
+// order they first appear.
+func MergeBuckets(histograms ...[]MinuteBucket) []MinuteBucket {
+	type bounds struct{ from, to int }
+	index := map[bounds]int{}
+	merged := []MinuteBucket{}
+	for _, h := range histograms {
+		for _, b := range h {
+			key := bounds{b.From, b.To}
+			i, ok := index[key]
+			if !ok {
+				i = len(merged)
+				index[key] = i
+				merged = append(merged, MinuteBucket{From: b.From, To: b.To})
+			}
+			merged[i].Count += b.Count
+		}
+	}
+
+	total := 0
+	for _, b := range merged {
+		total += b.Count
+	}
+	for i := range merged {
+		if total > 0 {
+			merged[i].Percentage = Float(100 * float64(merged[i].Count) / float64(total))
+		}
+	}
+	return merged
+}
+
+// GoalsForByMinute returns the distribution of the goals scored by the team.
+func (r TeamStatsResponse) GoalsForByMinute() []MinuteBucket {
+	return MergeBuckets(r.TeamStats.Goals.For.Minute.Buckets())
+}
+
+// GoalsAgainstByMinute returns the distribution of the goals conceded by the team.
+func (r TeamStatsResponse) GoalsAgainstByMinute() []MinuteBucket {
+	return MergeBuckets(r.TeamStats.Goals.Against.Minute.Buckets())
+}
+
+// YellowCardsByMinute returns the distribution of the yellow cards received by the team.
+func (r TeamStatsResponse) YellowCardsByMinute() []MinuteBucket {
+	return MergeBuckets(r.TeamStats.Cards.Yellow.Buckets())
+}
+
+// RedCardsByMinute returns the distribution of the red cards received by the team.
+func (r TeamStatsResponse) RedCardsByMinute() []MinuteBucket {
+	return MergeBuckets(r.TeamStats.Cards.Red.Buckets())
+}
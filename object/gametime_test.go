@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// goalsFor is the goals.for.minute object of a team statistics response. The api sends null
+// for periods without goals and, in many leagues, for extra time.
+const goalsFor = `{
+	"0-15": {"total": 4, "percentage": "12.50%"},
+	"16-30": {"total": 6, "percentage": "18.75%"},
+	"31-45": {"total": 5, "percentage": "15.63%"},
+	"46-60": {"total": 7, "percentage": "21.88%"},
+	"61-75": {"total": null, "percentage": null},
+	"76-90": {"total": 10, "percentage": "31.25%"},
+	"91-105": {"total": null, "percentage": null},
+	"105-120": {"total": null, "percentage": null}
+}`
+
+func TestBuckets(t *testing.T) {
+	var g GameTime
+	if err := json.Unmarshal([]byte(goalsFor), &g); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []MinuteBucket{
+		{0, 15, 4, Float(12.5)},
+		{16, 30, 6, Float(18.75)},
+		{31, 45, 5, Float(15.63)},
+		{46, 60, 7, Float(21.88)},
+		{61, 75, 0, NullFloat{}},
+		{76, 90, 10, Float(31.25)},
+		{91, 105, 0, NullFloat{}},
+		{106, 120, 0, NullFloat{}},
+	}
+	got := g.Buckets()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got buckets %v, want %v", got, want)
+	}
+}
+
+func TestMergeBuckets(t *testing.T) {
+	first := []MinuteBucket{
+		{0, 45, 3, Float(75)},
+		{46, 90, 1, Float(25)},
+	}
+	second := []MinuteBucket{
+		{46, 90, 4, Float(80)},
+		{91, 120, 1, Float(20)},
+	}
+
+	want := []MinuteBucket{
+		{0, 45, 3, Float(100 * 3.0 / 9)},
+		{46, 90, 5, Float(100 * 5.0 / 9)},
+		{91, 120, 1, Float(100 * 1.0 / 9)},
+	}
+	got := MergeBuckets(first, second)
+	if len(got) != len(want) {
+		t.Fatalf("got %d buckets, want %d", len(got), len(want))
+	}
+	total := 0.0
+	for i := range got {
+		g, w := got[i], want[i]
+		if g.From != w.From || g.To != w.To || g.Count != w.Count || !g.Percentage.Valid ||
+			math.Abs(g.Percentage.Value-w.Percentage.Value) > 1e-9 {
+			t.Errorf("bucket %d: got %v, want %v", i, g, w)
+		}
+		total += g.Percentage.Value
+	}
+	if math.Abs(total-100) > 1e-9 {
+		t.Errorf("got percentages adding up to %v, want 100", total)
+	}
+
+	// Without any count there is nothing to share, so no percentage is valid.
+	for _, b := range MergeBuckets([]MinuteBucket{{0, 15, 0, Float(0)}}) {
+		if b.Percentage.Valid {
+			t.Errorf("got percentage %v for empty buckets, want null", b.Percentage)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Formation is the number of players in each outfield line, from defence to attack. The
+// goalkeeper is not included, so "4-2-3-1" is Formation{4, 2, 3, 1}.
+type Formation []int
+
+// ParseFormation parses a formation string like "4-2-3-1".
+func ParseFormation(s string) (Formation, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	f := make(Formation, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid formation %q", s)
+		}
+		f[i] = n
+	}
+	return f, nil
+}
+
+// Players returns the number of players in the formation, including the goalkeeper.
+func (f Formation) Players() int {
+	n := 1
+	for _, line := range f {
+		n += line
+	}
+	return n
+}
+
+func (f Formation) String() string {
+	strs := make([]string, len(f))
+	for i, n := range f {
+		strs[i] = strconv.Itoa(n)
+	}
+	return strings.Join(strs, "-")
+}
+
+// ParseGrid parses the "row:col" grid of a starter. Row 1 is the goalkeeper and col 1 is the
+// first player of the row.
+func ParseGrid(grid string) (row, col int, err error) {
+	r, c, ok := strings.Cut(grid, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid grid %q", grid)
+	}
+	if row, err = strconv.Atoi(r); err != nil || row <= 0 {
+		return 0, 0, fmt.Errorf("invalid grid %q", grid)
+	}
+	if col, err = strconv.Atoi(c); err != nil || col <= 0 {
+		return 0, 0, fmt.Errorf("invalid grid %q", grid)
+	}
+	return row, col, nil
+}
+
+// PitchPosition is where a starter plays on the pitch. Row and Col are the lineup grid. X and Y
+// are in the [0, 1] range: Y goes from the team's own goal line to the opponent's and X grows
+// with the column, with players spread evenly across their row.
+type PitchPosition struct {
+	Player Player
+	Row    int
+	Col    int
+	X      float64
+	Y      float64
+}
+
+// ParsedFormation returns the parsed formation of the lineup.
+func (l Lineup) ParsedFormation() (Formation, error) {
+	return ParseFormation(l.Formation)
+}
+
+// Pitch maps each starter to a position on the pitch. The grid sent by the api is used when
+// every starter has one. Otherwise starters are assumed to be listed from the goalkeeper to
+// the attack and are placed according to the formation.
+func (l Lineup) Pitch() ([]PitchPosition, error) {
+	pos := make([]PitchPosition, len(l.StartXI))
+	rowSize := map[int]int{}
+	hasGrid := true
+	for i, s := range l.StartXI {
+		row, col, err := ParseGrid(s.Player.Grid)
+		if err != nil {
+			hasGrid = false
+			break
+		}
+		pos[i] = PitchPosition{Player: s.Player, Row: row, Col: col}
+		if col > rowSize[row] {
+			rowSize[row] = col
+		}
+	}
+
+	if !hasGrid {
+		f, err := l.ParsedFormation()
+		if err != nil {
+			return nil, err
+		}
+		if f.Players() != len(l.StartXI) {
+			return nil, fmt.Errorf("formation %s has %d players, lineup has %d", f, f.Players(), len(l.StartXI))
+		}
+
+		rowSize = map[int]int{1: 1}
+		lines := append(Formation{1}, f...)
+		i := 0
+		for r, n := range lines {
+			rowSize[r+1] = n
+			for c := 1; c <= n; c++ {
+				pos[i] = PitchPosition{Player: l.StartXI[i].Player, Row: r + 1, Col: c}
+				i++
+			}
+		}
+	}
+
+	rows := 0
+	for r := range rowSize {
+		if r > rows {
+			rows = r
+		}
+	}
+	for i := range pos {
+		p := &pos[i]
+		p.Y = (float64(p.Row) - 0.5) / float64(rows)
+		p.X = (float64(p.Col) - 0.5) / float64(rowSize[p.Row])
+	}
+	return pos, nil
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseFormation(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Formation
+		players int
+		wantErr bool
+	}{
+		{"4-2-3-1", Formation{4, 2, 3, 1}, 11, false},
+		{"3-5-2", Formation{3, 5, 2}, 11, false},
+		{" 4-4-2 ", Formation{4, 4, 2}, 11, false},
+		{"4-4-1-1", Formation{4, 4, 1, 1}, 11, false},
+		{"", nil, 0, true},
+		{"4-4-x", nil, 0, true},
+		{"4-0-6", nil, 0, true},
+		{"4--4-2", nil, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormation(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormation(%q): got error %v, want error %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("ParseFormation(%q): got %v, want %v", tt.s, got, tt.want)
+		}
+		if err == nil && got.Players() != tt.players {
+			t.Errorf("ParseFormation(%q): got %d players, want %d", tt.s, got.Players(), tt.players)
+		}
+	}
+}
+
+// lineup433 returns a 4-3-3 lineup as sent by the api, with players numbered 1 to 11 from the
+// goalkeeper to the attack. If grid is false the players have no grid.
+func lineup433(t *testing.T, grid bool) Lineup {
+	t.Helper()
+	players := []string{}
+	id := 1
+	for row, n := range []int{1, 4, 3, 3} {
+		for col := 1; col <= n; col++ {
+			g := "null"
+			if grid {
+				g = fmt.Sprintf(`"%d:%d"`, row+1, col)
+			}
+			players = append(players, fmt.Sprintf(`{"player": {"id": %d, "number": %d, "grid": %s}}`, id, id, g))
+			id++
+		}
+	}
+	body := fmt.Sprintf(`{"team": {"id": 50}, "formation": "4-3-3", "startXI": [%s]}`, strings.Join(players, ","))
+
+	var l Lineup
+	if err := json.Unmarshal([]byte(body), &l); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestPitch(t *testing.T) {
+	want := map[int]PitchPosition{
+		1:  {Row: 1, Col: 1, X: 0.5, Y: 0.125},
+		2:  {Row: 2, Col: 1, X: 0.125, Y: 0.375},
+		5:  {Row: 2, Col: 4, X: 0.875, Y: 0.375},
+		7:  {Row: 3, Col: 2, X: 0.5, Y: 0.625},
+		11: {Row: 4, Col: 3, X: 5.0 / 6, Y: 0.875},
+	}
+
+	for _, grid := range []bool{true, false} {
+		t.Run(fmt.Sprintf("grid %v", grid), func(t *testing.T) {
+			pos, err := lineup433(t, grid).Pitch()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pos) != 11 {
+				t.Fatalf("got %d positions, want 11", len(pos))
+			}
+			for _, p := range pos {
+				w, ok := want[p.Player.ID]
+				if !ok {
+					continue
+				}
+				if p.Row != w.Row || p.Col != w.Col || math.Abs(p.X-w.X) > 1e-9 || math.Abs(p.Y-w.Y) > 1e-9 {
+					t.Errorf("player %d: got %d:%d at (%.3f, %.3f), want %d:%d at (%.3f, %.3f)",
+						p.Player.ID, p.Row, p.Col, p.X, p.Y, w.Row, w.Col, w.X, w.Y)
+				}
+			}
+		})
+	}
+
+	l := lineup433(t, false)
+	l.Formation = "4-4-3"
+	if _, err := l.Pitch(); err == nil {
+		t.Error("got no error for a formation that does not match the lineup")
+	}
+	l.Formation = ""
+	if _, err := l.Pitch(); err == nil {
+		t.Error("got no error for a lineup without grid or formation")
+	}
+}
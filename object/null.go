@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NullInt is an integer that may not have been recorded by the api. Valid is false when the
+// api sent null.
+type NullInt struct {
+	Value int
+	Valid bool
+}
+
+// NullFloat is a number that may not have been recorded by the api. Valid is false when the
+// api sent null. Percentages such as "55%" are parsed as 55.
+type NullFloat struct {
+	Value float64
+	Valid bool
+}
+
+// Int returns a valid NullInt holding v.
+func Int(v int) NullInt {
+	return NullInt{Value: v, Valid: true}
+}
+
+// Float returns a valid NullFloat holding v.
+func Float(v float64) NullFloat {
+	return NullFloat{Value: v, Valid: true}
+}
+
+// Add returns the sum of n and o. The sum is valid if either of them is.
+func (n NullInt) Add(o NullInt) NullInt {
+	return NullInt{Value: n.Value + o.Value, Valid: n.Valid || o.Valid}
+}
+
+// Add returns the sum of n and o. The sum is valid if either of them is.
+func (n NullFloat) Add(o NullFloat) NullFloat {
+	return NullFloat{Value: n.Value + o.Value, Valid: n.Valid || o.Valid}
+}
+
+func (n NullInt) String() string {
+	if !n.Valid {
+		return "null"
+	}
+	return strconv.Itoa(n.Value)
+}
+
+func (n NullFloat) String() string {
+	if !n.Valid {
+		return "null"
+	}
+	return strconv.FormatFloat(n.Value, 'f', -1, 64)
+}
+
+func (n *NullInt) UnmarshalJSON(b []byte) error {
+	f, valid, err := parseNullNumber(b)
+	if err != nil {
+		return err
+	}
+	*n = NullInt{Value: int(math.Round(f)), Valid: valid}
+	return nil
+}
+
+func (n NullInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+func (n *NullFloat) UnmarshalJSON(b []byte) error {
+	f, valid, err := parseNullNumber(b)
+	if err != nil {
+		return err
+	}
+	*n = NullFloat{Value: f, Valid: valid}
+	return nil
+}
+
+func (n NullFloat) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// parseNullNumber decodes b into a number. Values that are not numbers are treated as not
+// recorded instead of failing the whole response.
+func parseNullNumber(b []byte) (float64, bool, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return 0, false, err
+	}
+	f, valid, err := toNullNumber(v)
+	if err != nil {
+		return 0, false, nil
+	}
+	return f, valid, nil
+}
+
+// toNullNumber converts a decoded json value that is either null, a number or a string
+// holding a number, with an optional percent sign.
+func toNullNumber(v interface{}) (float64, bool, error) {
+	switch val := v.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return val, true, nil
+	case string:
+		str := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(val), "%"))
+		if str == "" {
+			return 0, false, nil
+		}
+		f, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid number %q: %w", val, err)
+		}
+		return f, true, nil
+	}
+	return 0, false, fmt.Errorf("invalid number: %v", v)
+}
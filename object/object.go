@@ -19,6 +19,7 @@
 package object
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -32,16 +33,38 @@ type commonResponse struct {
 	Results    int         `json:"results"`
 	Paging     PagingToken `json:"paging"`
 	Timestamp  int64
+
+	// RawEnvelope is the whole json document returned by the api and RawResponse its
+	// "response" field. They are only set when the client is created with fball.WithRawJSON.
+	RawEnvelope json.RawMessage `json:"-"`
+	RawResponse json.RawMessage `json:"-"`
 }
 
 func (cr *commonResponse) SetWhen(timestamp int64) {
 	cr.Timestamp = timestamp
 }
 
+func (cr *commonResponse) SetRaw(envelope, response json.RawMessage) {
+	cr.RawEnvelope = envelope
+	cr.RawResponse = response
+}
+
+func (cr commonResponse) Raw() json.RawMessage {
+	return cr.RawEnvelope
+}
+
 func (cr commonResponse) When() int64 {
 	return cr.Timestamp
 }
 
+func (cr commonResponse) Count() int {
+	return cr.Results
+}
+
+func (cr commonResponse) Page() PagingToken {
+	return cr.Paging
+}
+
 func (cr commonResponse) Err() error {
 	if cr.Errors == nil {
 		return nil
@@ -105,13 +128,13 @@ type LeagueInfoResponse struct {
 }
 
 type LeagueInfo struct {
-	League  League  `json:"league"`
-	Country Country `json:"country"`
+	League  LeagueDetail `json:"league"`
+	Country Country      `json:"country"`
 	Seasons []struct {
-		Year     int    `json:"year"`
-		Start    string `json:"start"`
-		End      string `json:"end"`
-		Current  bool   `json:"current"`
+		Year     int  `json:"year"`
+		Start    Time `json:"start"`
+		End      Time `json:"end"`
+		Current  bool `json:"current"`
 		Coverage struct {
 			Fixtures struct {
 				Events             bool `json:"events"`
@@ -142,9 +165,14 @@ type Ranking struct {
 	All         RankTotals `json:"all"`
 	Home        RankTotals `json:"home"`
 	Away        RankTotals `json:"away"`
-	Update      string     `json:"update"`
+	Update      Time       `json:"update"`
 }
 
+// League has the fields of every league object returned by the api. Each endpoint returns
+// only some of them, so responses use the endpoint specific types instead: LeagueDetail,
+// StandingsLeague, FixtureLeague and TeamStatsLeague. This is a breaking change for code that
+// assigns those fields to a League or reads fields the endpoint never sets, e.g. the Type of a
+// fixture league. Their League method converts them to a League.
 type League struct {
 	ID       int         `json:"id"`
 	Name     string      `json:"name"`
@@ -157,6 +185,86 @@ type League struct {
 	Rankings [][]Ranking `json:"standings"`
 }
 
+// LeagueDetail is the league returned by the leagues endpoint.
+type LeagueDetail struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Logo string `json:"logo"`
+}
+
+// StandingsLeague is the league returned by the standings endpoint. Rankings has one table per
+// group of the league.
+type StandingsLeague struct {
+	ID       int         `json:"id"`
+	Name     string      `json:"name"`
+	Country  string      `json:"country"`
+	Logo     string      `json:"logo"`
+	Flag     string      `json:"flag"`
+	Season   int         `json:"season"`
+	Rankings [][]Ranking `json:"standings"`
+}
+
+// FixtureLeague is the league of a fixture.
+type FixtureLeague struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	Logo    string `json:"logo"`
+	Flag    string `json:"flag"`
+	Season  int    `json:"season"`
+	Round   string `json:"round"`
+}
+
+// TeamStatsLeague is the league returned by the team statistics endpoint.
+type TeamStatsLeague struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	Logo    string `json:"logo"`
+	Flag    string `json:"flag"`
+	Season  int    `json:"season"`
+}
+
+func (l LeagueDetail) League() League {
+	return League{ID: l.ID, Name: l.Name, Type: l.Type, Logo: l.Logo}
+}
+
+func (l StandingsLeague) League() League {
+	return League{
+		ID:       l.ID,
+		Name:     l.Name,
+		Country:  l.Country,
+		Logo:     l.Logo,
+		Flag:     l.Flag,
+		Season:   l.Season,
+		Rankings: l.Rankings,
+	}
+}
+
+func (l FixtureLeague) League() League {
+	return League{
+		ID:      l.ID,
+		Name:    l.Name,
+		Country: l.Country,
+		Logo:    l.Logo,
+		Flag:    l.Flag,
+		Season:  l.Season,
+		Round:   l.Round,
+	}
+}
+
+func (l TeamStatsLeague) League() League {
+	return League{
+		ID:      l.ID,
+		Name:    l.Name,
+		Country: l.Country,
+		Logo:    l.Logo,
+		Flag:    l.Flag,
+		Season:  l.Season,
+	}
+}
+
 type TeamInfoResponse struct {
 	commonResponse
 	TeamInfo []TeamInfo `json:"response"`
@@ -179,7 +287,7 @@ type TeamData struct {
 	Founded  int    `json:"founded"`
 	National bool   `json:"national"`
 	Logo     string `json:"logo"`
-	Update   string
+	Update   Time   `json:"update"`
 }
 
 type Venue struct {
@@ -196,7 +304,7 @@ type Venue struct {
 type StandingsResponse struct {
 	commonResponse
 	Standings []struct {
-		League League `json:"league"`
+		League StandingsLeague `json:"league"`
 	} `json:"response"`
 }
 
@@ -215,9 +323,9 @@ type TeamStatsResponse struct {
 	commonResponse
 
 	TeamStats struct {
-		League   League   `json:"league"`
-		Team     TeamData `json:"team"`
-		Form     string   `json:"form"`
+		League   TeamStatsLeague `json:"league"`
+		Team     TeamData        `json:"team"`
+		Form     string          `json:"form"`
 		Fixtures struct {
 			Played Totals `json:"played"`
 			Wins   Totals `json:"wins"`
@@ -298,22 +406,31 @@ type TotalPercent struct {
 type FixtureInfoResponse struct {
 	commonResponse
 
-	FixtureInfo []Head2Head `json:"response"`
+	FixtureInfo []FixtureDetail `json:"response"`
 }
 
-type H2HTeam struct {
+// H2HTeam is the old name of FixtureTeam.
+type H2HTeam = FixtureTeam
+
+type FixtureTeam struct {
 	ID     int    `json:"id"`
 	Name   string `json:"name"`
 	Logo   string `json:"logo"`
 	Winner bool   `json:"winner"`
 }
 
-type Head2Head struct {
-	Fixture Fixture `json:"fixture"`
-	League  League  `json:"league"`
+// Head2Head is the old name of FixtureDetail, which is returned by both the fixtures and the
+// head to head endpoints.
+type Head2Head = FixtureDetail
+
+// FixtureDetail is a fixture with its result. Events, Statistics, Lineups and Players are only
+// set when fetching fixtures by id.
+type FixtureDetail struct {
+	Fixture Fixture       `json:"fixture"`
+	League  FixtureLeague `json:"league"`
 	Teams   struct {
-		Home H2HTeam `json:"home"`
-		Away H2HTeam `json:"away"`
+		Home FixtureTeam `json:"home"`
+		Away FixtureTeam `json:"away"`
 	} `json:"teams"`
 	Goals Totals `json:"goals"`
 	Score struct {
@@ -332,7 +449,7 @@ type Fixture struct {
 	ID        int    `json:"id"`
 	Referee   string `json:"referee"`
 	Timezone  string `json:"timezone"`
-	Date      string `json:"date"`
+	Date      Time   `json:"date"`
 	Timestamp int64  `json:"timestamp"`
 	Periods   struct {
 		First  int `json:"first"`
@@ -340,16 +457,16 @@ type Fixture struct {
 	} `json:"periods"`
 	Venue  Venue `json:"venue"`
 	Status struct {
-		Long    string `json:"long"`
-		Short   string `json:"short"`
-		Elapsed int    `json:"elapsed"`
+		Long    string        `json:"long"`
+		Short   FixtureStatus `json:"short"`
+		Elapsed int           `json:"elapsed"`
 	} `json:"status"`
 }
 
 type Head2HeadResponse struct {
 	commonResponse
 
-	Head2Head []Head2Head `json:"response"`
+	Head2Head []FixtureDetail `json:"response"`
 }
 
 type FixtureStatsResponse struct {
@@ -377,12 +494,12 @@ type Event struct {
 		Elapsed int `json:"elapsed"`
 		Extra   int `json:"extra"`
 	} `json:"time"`
-	Team     TeamData `json:"team"`
-	Player   Player   `json:"player"`
-	Assist   Player   `json:"assist"`
-	Type     string   `json:"type"`
-	Detail   string   `json:"detail"`
-	Comments string   `json:"comments"`
+	Team     TeamData    `json:"team"`
+	Player   Player      `json:"player"`
+	Assist   Player      `json:"assist"`
+	Type     EventType   `json:"type"`
+	Detail   EventDetail `json:"detail"`
+	Comments string      `json:"comments"`
 }
 
 type Player struct {
@@ -390,6 +507,8 @@ type Player struct {
 	Name   string `json:"name"`
 	Number int    `json:"number"`
 	Pos    string `json:"pos"`
+	// Grid is the "row:col" position of a starter in the lineup. See Lineup.Pitch.
+	Grid string `json:"grid"`
 }
 
 type LineupResponse struct {
@@ -399,7 +518,7 @@ type LineupResponse struct {
 }
 
 type Lineup struct {
-	Team  TeamData `json:"team"`
+	Team  LineupTeam `json:"team"`
 	Coach struct {
 		ID    int    `json:"id"`
 		Name  string `json:"name"`
@@ -414,6 +533,21 @@ type Lineup struct {
 	} `json:"substitutes"`
 }
 
+type LineupTeam struct {
+	TeamData
+	Colors struct {
+		Player     KitColors `json:"player"`
+		Goalkeeper KitColors `json:"goalkeeper"`
+	} `json:"colors"`
+}
+
+// KitColors are hex rgb colors, without the leading #.
+type KitColors struct {
+	Primary string `json:"primary"`
+	Number  string `json:"number"`
+	Border  string `json:"border"`
+}
+
 type PlayerStatsResponse struct {
 	commonResponse
 
@@ -421,65 +555,73 @@ type PlayerStatsResponse struct {
 }
 
 type PlayerStats struct {
-	Team    TeamData `json:"team"`
-	Players []struct {
-		Player     Player `json:"player"`
-		Statistics []struct {
-			Games struct {
-				Minutes    int    `json:"minutes"`
-				Number     int    `json:"number"`
-				Position   string `json:"position"`
-				Rating     string `json:"rating"`
-				Captain    bool   `json:"captain"`
-				Substitute bool   `json:"substitute"`
-			} `json:"games"`
-			Offsides interface{} `json:"offsides"`
-			Shots    struct {
-				Total int `json:"total"`
-				On    int `json:"on"`
-			} `json:"shots"`
-			Goals struct {
-				Total    int `json:"total"`
-				Conceded int `json:"conceded"`
-				Assists  int `json:"assists"`
-				Saves    int `json:"saves"`
-			} `json:"goals"`
-			Passes struct {
-				Total    int    `json:"total"`
-				Key      int    `json:"key"`
-				Accuracy string `json:"accuracy"`
-			} `json:"passes"`
-			Tackles struct {
-				Total         int `json:"total"`
-				Blocks        int `json:"blocks"`
-				Interceptions int `json:"interceptions"`
-			} `json:"tackles"`
-			Duels struct {
-				Total int `json:"total"`
-				Won   int `json:"won"`
-			} `json:"duels"`
-			Dribbles struct {
-				Attempts int `json:"attempts"`
-				Success  int `json:"success"`
-				Past     int `json:"past"`
-			} `json:"dribbles"`
-			Fouls struct {
-				Drawn     int `json:"drawn"`
-				Committed int `json:"committed"`
-			} `json:"fouls"`
-			Cards struct {
-				Yellow int `json:"yellow"`
-				Red    int `json:"red"`
-			} `json:"cards"`
-			Penalty struct {
-				Won      int `json:"won"`
-				Commited int `json:"commited"`
-				Scored   int `json:"scored"`
-				Missed   int `json:"missed"`
-				Saved    int `json:"saved"`
-			} `json:"penalty"`
-		} `json:"statistics"`
-	} `json:"players"`
+	Team    TeamData             `json:"team"`
+	Players []PlayerFixtureStats `json:"players"`
+}
+
+type PlayerFixtureStats struct {
+	Player     Player            `json:"player"`
+	Statistics []PlayerGameStats `json:"statistics"`
+}
+
+// PlayerGameStats are the stats of a player in a fixture. Numbers the api did not record are
+// not valid, which is different from a recorded zero.
+type PlayerGameStats struct {
+	Games struct {
+		Minutes  NullInt `json:"minutes"`
+		Number   NullInt `json:"number"`
+		Position string  `json:"position"`
+		// Rating is the api rating, from 0 to 10.
+		Rating     NullFloat `json:"rating"`
+		Captain    bool      `json:"captain"`
+		Substitute bool      `json:"substitute"`
+	} `json:"games"`
+	Offsides NullInt `json:"offsides"`
+	Shots    struct {
+		Total NullInt `json:"total"`
+		On    NullInt `json:"on"`
+	} `json:"shots"`
+	Goals struct {
+		Total    NullInt `json:"total"`
+		Conceded NullInt `json:"conceded"`
+		Assists  NullInt `json:"assists"`
+		Saves    NullInt `json:"saves"`
+	} `json:"goals"`
+	Passes struct {
+		Total NullInt `json:"total"`
+		Key   NullInt `json:"key"`
+		// Accuracy is the number of accurate passes. See PassAccuracy for the percentage.
+		Accuracy NullFloat `json:"accuracy"`
+	} `json:"passes"`
+	Tackles struct {
+		Total         NullInt `json:"total"`
+		Blocks        NullInt `json:"blocks"`
+		Interceptions NullInt `json:"interceptions"`
+	} `json:"tackles"`
+	Duels struct {
+		Total NullInt `json:"total"`
+		Won   NullInt `json:"won"`
+	} `json:"duels"`
+	Dribbles struct {
+		Attempts NullInt `json:"attempts"`
+		Success  NullInt `json:"success"`
+		Past     NullInt `json:"past"`
+	} `json:"dribbles"`
+	Fouls struct {
+		Drawn     NullInt `json:"drawn"`
+		Committed NullInt `json:"committed"`
+	} `json:"fouls"`
+	Cards struct {
+		Yellow NullInt `json:"yellow"`
+		Red    NullInt `json:"red"`
+	} `json:"cards"`
+	Penalty struct {
+		Won      NullInt `json:"won"`
+		Commited NullInt `json:"commited"`
+		Scored   NullInt `json:"scored"`
+		Missed   NullInt `json:"missed"`
+		Saved    NullInt `json:"saved"`
+	} `json:"penalty"`
 }
 
 type PagingToken struct {
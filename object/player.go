@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+// Played returns true if the player was on the pitch.
+func (s PlayerGameStats) Played() bool {
+	return s.Games.Minutes.Valid && s.Games.Minutes.Value > 0
+}
+
+// PassAccuracy returns the percentage of accurate passes.
+func (s PlayerGameStats) PassAccuracy() NullFloat {
+	if !s.Passes.Accuracy.Valid || !s.Passes.Total.Valid || s.Passes.Total.Value == 0 {
+		return NullFloat{}
+	}
+	return Float(100 * s.Passes.Accuracy.Value / float64(s.Passes.Total.Value))
+}
+
+// SumPlayerStats sums the stats of a player across fixtures. A count is valid if it was
+// recorded in at least one fixture. The rating is the average of the recorded ratings,
+// weighted by minutes played. If any rated fixture has no minutes, the weights are unknown and
+// the rating is the plain average instead. Position, number, captain and substitute come from
+// the last fixture in which the player played.
+func SumPlayerStats(stats []PlayerGameStats) PlayerGameStats {
+	sum := PlayerGameStats{}
+	ratings, ratingSum, weightedSum, minutesSum := 0, 0.0, 0.0, 0
+	allMinutes := true
+	for _, s := range stats {
+		if s.Played() {
+			sum.Games.Number = s.Games.Number
+			sum.Games.Position = s.Games.Position
+			sum.Games.Captain = s.Games.Captain
+			sum.Games.Substitute = s.Games.Substitute
+		}
+		if s.Games.Rating.Valid {
+			ratings++
+			ratingSum += s.Games.Rating.Value
+			if s.Played() {
+				weightedSum += s.Games.Rating.Value * float64(s.Games.Minutes.Value)
+				minutesSum += s.Games.Minutes.Value
+			} else {
+				allMinutes = false
+			}
+		}
+		sum.Games.Minutes = sum.Games.Minutes.Add(s.Games.Minutes)
+
+		sum.Offsides = sum.Offsides.Add(s.Offsides)
+		sum.Shots.Total = sum.Shots.Total.Add(s.Shots.Total)
+		sum.Shots.On = sum.Shots.On.Add(s.Shots.On)
+		sum.Goals.Total = sum.Goals.Total.Add(s.Goals.Total)
+		sum.Goals.Conceded = sum.Goals.Conceded.Add(s.Goals.Conceded)
+		sum.Goals.Assists = sum.Goals.Assists.Add(s.Goals.Assists)
+		sum.Goals.Saves = sum.Goals.Saves.Add(s.Goals.Saves)
+		sum.Passes.Total = sum.Passes.Total.Add(s.Passes.Total)
+		sum.Passes.Key = sum.Passes.Key.Add(s.Passes.Key)
+		sum.Passes.Accuracy = sum.Passes.Accuracy.Add(s.Passes.Accuracy)
+		sum.Tackles.Total = sum.Tackles.Total.Add(s.Tackles.Total)
+		sum.Tackles.Blocks = sum.Tackles.Blocks.Add(s.Tackles.Blocks)
+		sum.Tackles.Interceptions = sum.Tackles.Interceptions.Add(s.Tackles.Interceptions)
+		sum.Duels.Total = sum.Duels.Total.Add(s.Duels.Total)
+		sum.Duels.Won = sum.Duels.Won.Add(s.Duels.Won)
+		sum.Dribbles.Attempts = sum.Dribbles.Attempts.Add(s.Dribbles.Attempts)
+		sum.Dribbles.Success = sum.Dribbles.Success.Add(s.Dribbles.Success)
+		sum.Dribbles.Past = sum.Dribbles.Past.Add(s.Dribbles.Past)
+		sum.Fouls.Drawn = sum.Fouls.Drawn.Add(s.Fouls.Drawn)
+		sum.Fouls.Committed = sum.Fouls.Committed.Add(s.Fouls.Committed)
+		sum.Cards.Yellow = sum.Cards.Yellow.Add(s.Cards.Yellow)
+		sum.Cards.Red = sum.Cards.Red.Add(s.Cards.Red)
+		sum.Penalty.Won = sum.Penalty.Won.Add(s.Penalty.Won)
+		sum.Penalty.Commited = sum.Penalty.Commited.Add(s.Penalty.Commited)
+		sum.Penalty.Scored = sum.Penalty.Scored.Add(s.Penalty.Scored)
+		sum.Penalty.Missed = sum.Penalty.Missed.Add(s.Penalty.Missed)
+		sum.Penalty.Saved = sum.Penalty.Saved.Add(s.Penalty.Saved)
+	}
+	switch {
+	case ratings > 0 && allMinutes:
+		sum.Games.Rating = Float(weightedSum / float64(minutesSum))
+	case ratings > 0:
+		sum.Games.Rating = Float(ratingSum / float64(ratings))
+	}
+	return sum
+}
+
+// PlayerStatsTotal is the sum of the stats of a player across fixtures.
+type PlayerStatsTotal struct {
+	Player Player
+	// Team is the last team the player played for.
+	Team TeamData
+	// Fixtures is the number of fixtures with stats for the player and Appearances the
+	// number of those in which the player was on the pitch.
+	Fixtures    int
+	Appearances int
+	Stats       PlayerGameStats
+}
+
+// TotalPlayerStats sums the stats of each player found in stats, which can be the teams of
+// any number of PlayerStatsResponse or FixtureDetail. Players are returned in the order they
+// first appear.
+func TotalPlayerStats(stats []PlayerStats) []PlayerStatsTotal {
+	order := []int{}
+	totals := map[int]*PlayerStatsTotal{}
+	games := map[int][]PlayerGameStats{}
+	for _, team := range stats {
+		for _, p := range team.Players {
+			total, ok := totals[p.Player.ID]
+			if !ok {
+				total = &PlayerStatsTotal{Player: p.Player}
+				totals[p.Player.ID] = total
+				order = append(order, p.Player.ID)
+			}
+			total.Team = team.Team
+			for _, s := range p.Statistics {
+				total.Fixtures++
+				if s.Played() {
+					total.Appearances++
+				}
+				games[p.Player.ID] = append(games[p.Player.ID], s)
+			}
+		}
+	}
+
+	res := make([]PlayerStatsTotal, 0, len(order))
+	for _, id := range order {
+		total := totals[id]
+		total.Stats = SumPlayerStats(games[id])
+		res = append(res, *total)
+	}
+	return res
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// gameStats decodes the statistics of a player in a fixture, as sent by the api.
+func gameStats(t *testing.T, body string) PlayerGameStats {
+	t.Helper()
+	var s PlayerGameStats
+	if err := json.Unmarshal([]byte(body), &s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSumPlayerStats(t *testing.T) {
+	tests := []struct {
+		name   string
+		stats  []string
+		rating NullFloat
+	}{
+		{
+			name: "weighted by minutes",
+			stats: []string{
+				`{"games": {"minutes": 90, "rating": "7.0"}}`,
+				`{"games": {"minutes": 30, "rating": "6.0"}}`,
+			},
+			rating: Float(6.75),
+		},
+		{
+			name: "fixtures without a rating are left out",
+			stats: []string{
+				`{"games": {"minutes": 90, "rating": "7.0"}}`,
+				`{"games": {"minutes": 10, "rating": null}}`,
+				`{"games": {"minutes": null, "rating": null}}`,
+			},
+			rating: Float(7),
+		},
+		{
+			name: "plain average when a rated fixture has no minutes",
+			stats: []string{
+				`{"games": {"minutes": 90, "rating": "7.0"}}`,
+				`{"games": {"minutes": null, "rating": "6.0"}}`,
+				`{"games": {"minutes": 30, "rating": "8.0"}}`,
+			},
+			rating: Float(7),
+		},
+		{
+			name: "no ratings",
+			stats: []string{
+				`{"games": {"minutes": 90, "rating": null}}`,
+			},
+			rating: NullFloat{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := []PlayerGameStats{}
+			for _, body := range tt.stats {
+				stats = append(stats, gameStats(t, body))
+			}
+			got := SumPlayerStats(stats).Games.Rating
+			if got.Valid != tt.rating.Valid || math.Abs(got.Value-tt.rating.Value) > 1e-9 {
+				t.Errorf("got rating %v, want %v", got, tt.rating)
+			}
+		})
+	}
+}
+
+func TestSumPlayerStatsCounts(t *testing.T) {
+	sum := SumPlayerStats([]PlayerGameStats{
+		gameStats(t, `{
+			"games": {"minutes": 90, "number": 7, "position": "F", "rating": "7.3", "captain": true},
+			"goals": {"total": 1, "assists": null, "saves": null},
+			"passes": {"total": 30, "key": 2, "accuracy": "24"},
+			"cards": {"yellow": 0, "red": 0}
+		}`),
+		gameStats(t, `{
+			"games": {"minutes": 20, "number": 9, "position": "M", "rating": "6.8", "substitute": true},
+			"goals": {"total": null, "assists": 1, "saves": null},
+			"passes": {"total": 10, "key": null, "accuracy": "6"},
+			"cards": {"yellow": 1, "red": 0}
+		}`),
+		// On the bench for the whole game.
+		gameStats(t, `{
+			"games": {"minutes": null, "number": 12, "position": "G", "rating": null, "substitute": true},
+			"goals": {"total": null, "assists": null, "saves": null},
+			"passes": {"total": null, "key": null, "accuracy": null},
+			"cards": {"yellow": 0, "red": 0}
+		}`),
+	})
+
+	counts := []struct {
+		name string
+		got  NullInt
+		want NullInt
+	}{
+		{"minutes", sum.Games.Minutes, Int(110)},
+		{"goals", sum.Goals.Total, Int(1)},
+		{"assists", sum.Goals.Assists, Int(1)},
+		{"saves", sum.Goals.Saves, NullInt{}},
+		{"passes", sum.Passes.Total, Int(40)},
+		{"key passes", sum.Passes.Key, Int(2)},
+		{"yellow cards", sum.Cards.Yellow, Int(1)},
+		{"red cards", sum.Cards.Red, Int(0)},
+	}
+	for _, tt := range counts {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if got := sum.PassAccuracy(); got != Float(75) {
+		t.Errorf("got pass accuracy %v, want 75", got)
+	}
+	if sum.Games.Number != Int(9) || sum.Games.Position != "M" || sum.Games.Captain || !sum.Games.Substitute {
+		t.Errorf("got games %+v, want the ones of the last fixture played", sum.Games)
+	}
+}
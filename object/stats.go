@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import "math"
+
+// Statistic types returned by the fixture statistics endpoint.
+const (
+	StatShotsOnGoal     = "Shots on Goal"
+	StatShotsOffGoal    = "Shots off Goal"
+	StatTotalShots      = "Total Shots"
+	StatBlockedShots    = "Blocked Shots"
+	StatShotsInsideBox  = "Shots insidebox"
+	StatShotsOutsideBox = "Shots outsidebox"
+	StatFouls           = "Fouls"
+	StatCornerKicks     = "Corner Kicks"
+	StatOffsides        = "Offsides"
+	StatBallPossession  = "Ball Possession"
+	StatYellowCards     = "Yellow Cards"
+	StatRedCards        = "Red Cards"
+	StatGoalkeeperSaves = "Goalkeeper Saves"
+	StatTotalPasses     = "Total passes"
+	StatPassesAccurate  = "Passes accurate"
+	StatPassAccuracy    = "Passes %"
+	StatExpectedGoals   = "expected_goals"
+	StatGoalsPrevented  = "goals_prevented"
+)
+
+// TeamMatchStats is a typed view of the statistics of a team in a fixture. Percentages are in
+// the 0-100 range. Statistics the api did not record are not valid.
+type TeamMatchStats struct {
+	Team TeamData
+
+	ShotsOnGoal     NullInt
+	ShotsOffGoal    NullInt
+	TotalShots      NullInt
+	BlockedShots    NullInt
+	ShotsInsideBox  NullInt
+	ShotsOutsideBox NullInt
+	Fouls           NullInt
+	CornerKicks     NullInt
+	Offsides        NullInt
+	BallPossession  NullFloat
+	YellowCards     NullInt
+	RedCards        NullInt
+	GoalkeeperSaves NullInt
+	TotalPasses     NullInt
+	PassesAccurate  NullInt
+	PassAccuracy    NullFloat
+	ExpectedGoals   NullFloat
+	GoalsPrevented  NullFloat
+
+	// Other holds the statistics of unknown types, with their values as sent by the api.
+	Other map[string]interface{}
+}
+
+// Typed returns the typed view of the statistics.
+func (s Statistics) Typed() TeamMatchStats {
+	ts := TeamMatchStats{
+		Team:  s.Team,
+		Other: map[string]interface{}{},
+	}
+	ints := map[string]*NullInt{
+		StatShotsOnGoal:     &ts.ShotsOnGoal,
+		StatShotsOffGoal:    &ts.ShotsOffGoal,
+		StatTotalShots:      &ts.TotalShots,
+		StatBlockedShots:    &ts.BlockedShots,
+		StatShotsInsideBox:  &ts.ShotsInsideBox,
+		StatShotsOutsideBox: &ts.ShotsOutsideBox,
+		StatFouls:           &ts.Fouls,
+		StatCornerKicks:     &ts.CornerKicks,
+		StatOffsides:        &ts.Offsides,
+		StatYellowCards:     &ts.YellowCards,
+		StatRedCards:        &ts.RedCards,
+		StatGoalkeeperSaves: &ts.GoalkeeperSaves,
+		StatTotalPasses:     &ts.TotalPasses,
+		StatPassesAccurate:  &ts.PassesAccurate,
+	}
+	floats := map[string]*NullFloat{
+		StatBallPossession: &ts.BallPossession,
+		StatPassAccuracy:   &ts.PassAccuracy,
+		StatExpectedGoals:  &ts.ExpectedGoals,
+		StatGoalsPrevented: &ts.GoalsPrevented,
+	}
+
+	for _, info := range s.Info {
+		f, valid, err := toNullNumber(info.Value)
+		if n, ok := ints[info.Type]; ok && err == nil {
+			*n = NullInt{Value: int(math.Round(f)), Valid: valid}
+		} else if n, ok := floats[info.Type]; ok && err == nil {
+			*n = NullFloat{Value: f, Valid: valid}
+		} else {
+			ts.Other[info.Type] = info.Value
+		}
+	}
+	return ts
+}
+
+// TypedStats returns the typed view of the statistics for each team.
+func (r FixtureStatsResponse) TypedStats() []TeamMatchStats {
+	stats := make([]TeamMatchStats, len(r.Statistics))
+	for i, s := range r.Statistics {
+		stats[i] = s.Typed()
+	}
+	return stats
+}
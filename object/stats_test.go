@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestToNullNumber(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		want    float64
+		valid   bool
+		wantErr bool
+	}{
+		{nil, 0, false, false},
+		{float64(12), 12, true, false},
+		{float64(0), 0, true, false},
+		{"55%", 55, true, false},
+		{" 45 % ", 45, true, false},
+		{"1.73", 1.73, true, false},
+		{"", 0, false, false},
+		{"%", 0, false, false},
+		{"n/a", 0, false, true},
+		{true, 0, false, true},
+	}
+	for _, tt := range tests {
+		got, valid, err := toNullNumber(tt.value)
+		if got != tt.want || valid != tt.valid || (err != nil) != tt.wantErr {
+			t.Errorf("toNullNumber(%#v): got %v, %v, %v, want %v, %v, error %v",
+				tt.value, got, valid, err, tt.want, tt.valid, tt.wantErr)
+		}
+	}
+}
+
+func TestNullJSON(t *testing.T) {
+	tests := []struct {
+		json      string
+		wantInt   string
+		wantFloat string
+	}{
+		{`null`, `null`, `null`},
+		{`7`, `7`, `7`},
+		{`"7.5"`, `8`, `7.5`},
+		{`"55%"`, `55`, `55`},
+		{`""`, `null`, `null`},
+		{`"n/a"`, `null`, `null`},
+	}
+	for _, tt := range tests {
+		var n NullInt
+		var f NullFloat
+		if err := json.Unmarshal([]byte(tt.json), &n); err != nil {
+			t.Errorf("%s: %v", tt.json, err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(tt.json), &f); err != nil {
+			t.Errorf("%s: %v", tt.json, err)
+			continue
+		}
+		if b, _ := json.Marshal(n); string(b) != tt.wantInt {
+			t.Errorf("%s: got NullInt %s, want %s", tt.json, b, tt.wantInt)
+		}
+		if b, _ := json.Marshal(f); string(b) != tt.wantFloat {
+			t.Errorf("%s: got NullFloat %s, want %s", tt.json, b, tt.wantFloat)
+		}
+	}
+}
+
+func TestTyped(t *testing.T) {
+	const body = `{
+		"team": {"id": 33, "name": "Manchester United"},
+		"statistics": [
+			{"type": "Shots on Goal", "value": 6},
+			{"type": "Shots off Goal", "value": "4"},
+			{"type": "Corner Kicks", "value": 0},
+			{"type": "Red Cards", "value": null},
+			{"type": "Ball Possession", "value": "55%"},
+			{"type": "expected_goals", "value": "1.73"},
+			{"type": "Passes %", "value": "n/a"},
+			{"type": "Throw ins", "value": 12}
+		]
+	}`
+	var s Statistics
+	if err := json.Unmarshal([]byte(body), &s); err != nil {
+		t.Fatal(err)
+	}
+	ts := s.Typed()
+
+	if ts.Team.ID != 33 {
+		t.Errorf("got team %d, want 33", ts.Team.ID)
+	}
+	ints := []struct {
+		name string
+		got  NullInt
+		want NullInt
+	}{
+		{"shots on goal", ts.ShotsOnGoal, Int(6)},
+		{"shots off goal", ts.ShotsOffGoal, Int(4)},
+		{"corner kicks", ts.CornerKicks, Int(0)},
+		{"red cards", ts.RedCards, NullInt{}},
+		{"fouls", ts.Fouls, NullInt{}},
+	}
+	for _, tt := range ints {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+	floats := []struct {
+		name string
+		got  NullFloat
+		want NullFloat
+	}{
+		{"ball possession", ts.BallPossession, Float(55)},
+		{"expected goals", ts.ExpectedGoals, Float(1.73)},
+		{"pass accuracy", ts.PassAccuracy, NullFloat{}},
+	}
+	for _, tt := range floats {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// A known type whose value does not parse is kept as sent, like unknown types.
+	want := map[string]interface{}{"Passes %": "n/a", "Throw ins": float64(12)}
+	if fmt.Sprint(ts.Other) != fmt.Sprint(want) {
+		t.Errorf("got other %v, want %v", ts.Other, want)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import "strings"
+
+// FixtureStatus is the short status code of a fixture.
+type FixtureStatus string
+
+const (
+	StatusTBD         FixtureStatus = "TBD"  // Time to be defined.
+	StatusNotStarted  FixtureStatus = "NS"   // Not started.
+	StatusFirstHalf   FixtureStatus = "1H"   // First half, kick off.
+	StatusHalftime    FixtureStatus = "HT"   // Halftime.
+	StatusSecondHalf  FixtureStatus = "2H"   // Second half, 2nd half started.
+	StatusExtraTime   FixtureStatus = "ET"   // Extra time.
+	StatusBreakTime   FixtureStatus = "BT"   // Break time in extra time.
+	StatusPenalty     FixtureStatus = "P"    // Penalty in progress.
+	StatusSuspended   FixtureStatus = "SUSP" // Match suspended.
+	StatusInterrupted FixtureStatus = "INT"  // Match interrupted.
+	StatusFinished    FixtureStatus = "FT"   // Match finished.
+	StatusAfterExtra  FixtureStatus = "AET"  // Match finished after extra time.
+	StatusAfterPen    FixtureStatus = "PEN"  // Match finished after penalty shootout.
+	StatusPostponed   FixtureStatus = "PST"  // Match postponed.
+	StatusCancelled   FixtureStatus = "CANC" // Match cancelled.
+	StatusAbandoned   FixtureStatus = "ABD"  // Match abandoned.
+	StatusAwarded     FixtureStatus = "AWD"  // Technical loss.
+	StatusWalkOver    FixtureStatus = "WO"   // WalkOver.
+	StatusLive        FixtureStatus = "LIVE" // In progress, used in very rare cases.
+)
+
+// StatusFilter combines statuses into a single value for the Status filter of the fixture
+// endpoints, e.g. StatusFilter(StatusFinished, StatusAfterExtra, StatusAfterPen).
+func StatusFilter(statuses ...FixtureStatus) FixtureStatus {
+	strs := make([]string, len(statuses))
+	for i, s := range statuses {
+		strs[i] = string(s)
+	}
+	return FixtureStatus(strings.Join(strs, "-"))
+}
+
+// IsKnown returns true if s is one of the documented status codes.
+func (s FixtureStatus) IsKnown() bool {
+	switch s {
+	case StatusTBD, StatusNotStarted, StatusFirstHalf, StatusHalftime, StatusSecondHalf,
+		StatusExtraTime, StatusBreakTime, StatusPenalty, StatusSuspended, StatusInterrupted,
+		StatusFinished, StatusAfterExtra, StatusAfterPen, StatusPostponed, StatusCancelled,
+		StatusAbandoned, StatusAwarded, StatusWalkOver, StatusLive:
+		return true
+	}
+	return false
+}
+
+// IsScheduled returns true if the fixture has not started yet.
+func (s FixtureStatus) IsScheduled() bool {
+	return s == StatusTBD || s == StatusNotStarted
+}
+
+// IsLive returns true if the fixture is in play, including breaks and stoppages.
+func (s FixtureStatus) IsLive() bool {
+	switch s {
+	case StatusFirstHalf, StatusHalftime, StatusSecondHalf, StatusExtraTime, StatusBreakTime,
+		StatusPenalty, StatusSuspended, StatusInterrupted, StatusLive:
+		return true
+	}
+	return false
+}
+
+// IsFinished returns true if the fixture has a final result. That includes fixtures decided
+// off the pitch, see IsPlayed.
+func (s FixtureStatus) IsFinished() bool {
+	return s.IsPlayed() || s == StatusAwarded || s == StatusWalkOver
+}
+
+// IsPlayed returns true if the fixture was played to the end.
+func (s FixtureStatus) IsPlayed() bool {
+	return s == StatusFinished || s == StatusAfterExtra || s == StatusAfterPen
+}
+
+// IsPostponed returns true if the fixture was postponed.
+func (s FixtureStatus) IsPostponed() bool {
+	return s == StatusPostponed
+}
+
+// IsCancelled returns true if the fixture was cancelled or abandoned.
+func (s FixtureStatus) IsCancelled() bool {
+	return s == StatusCancelled || s == StatusAbandoned
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Time is a date or timestamp returned by the api. Timestamps keep the offset sent by the
+// service, which is the timezone requested for the fixture endpoints and UTC for the others.
+// Dates without a time are parsed as midnight UTC. Raw keeps the value as it was received.
+type Time struct {
+	time.Time
+	Raw string
+}
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseTime parses a date or timestamp in any of the formats used by the api.
+func ParseTime(raw string) (Time, error) {
+	t := Time{Raw: raw}
+	if raw == "" {
+		return t, nil
+	}
+	for _, layout := range timeLayouts {
+		parsed, err := time.Parse(layout, raw)
+		if err == nil {
+			t.Time = parsed
+			return t, nil
+		}
+	}
+	return t, fmt.Errorf("invalid time %q", raw)
+}
+
+func (t *Time) UnmarshalJSON(b []byte) error {
+	var raw *string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*t = Time{}
+		return nil
+	}
+
+	// A format we don't know about should not fail the whole response, so keep the raw value
+	// and leave the time zero.
+	parsed, _ := ParseTime(*raw)
+	*t = parsed
+	return nil
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.Raw == "" && t.IsZero() {
+		return []byte("null"), nil
+	}
+	if t.Raw == "" {
+		return json.Marshal(t.Format(time.RFC3339))
+	}
+	return json.Marshal(t.Raw)
+}
+
+func (t Time) String() string {
+	if t.Raw == "" {
+		return t.Time.String()
+	}
+	return t.Raw
+}
+
+// Kickoff returns the kickoff time of the fixture in the fixture timezone. If the timezone is
+// not known, the offset sent by the api is used.
+func (f Fixture) Kickoff() time.Time {
+	kickoff := f.Date.Time
+	if kickoff.IsZero() && f.Timestamp != 0 {
+		kickoff = time.Unix(f.Timestamp, 0).UTC()
+	}
+	if loc, err := time.LoadLocation(f.Timezone); err == nil && f.Timezone != "" {
+		kickoff = kickoff.In(loc)
+	}
+	return kickoff
+}
+
+// KickoffIn returns the kickoff time of the fixture in zone, which is an IANA zone name like
+// the ones returned by the timezone endpoint.
+func (f Fixture) KickoffIn(zone string) (time.Time, error) {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return f.Kickoff().In(loc), nil
+}
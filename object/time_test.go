@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package object
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"2021-08-14T16:00:00+00:00", "2021-08-14T16:00:00Z", false},
+		{"2021-08-14T13:00:00-03:00", "2021-08-14T13:00:00-03:00", false},
+		{"2021-08-14T16:00:00", "2021-08-14T16:00:00Z", false},
+		{"2021-06-30 09:12:04", "2021-06-30T09:12:04Z", false},
+		{"2021-08-13", "2021-08-13T00:00:00Z", false},
+		{"", "0001-01-01T00:00:00Z", false},
+		{"13/08/2021", "0001-01-01T00:00:00Z", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTime(%q): got error %v, want error %v", tt.raw, err, tt.wantErr)
+		}
+		if s := got.Format(time.RFC3339); s != tt.want {
+			t.Errorf("ParseTime(%q): got %s, want %s", tt.raw, s, tt.want)
+		}
+		if got.Raw != tt.raw {
+			t.Errorf("ParseTime(%q): got raw %q", tt.raw, got.Raw)
+		}
+	}
+}
+
+func TestTimeJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+		zero bool
+	}{
+		{"timestamp", `"2021-08-14T16:00:00+00:00"`, `"2021-08-14T16:00:00+00:00"`, false},
+		{"date", `"2021-08-13"`, `"2021-08-13"`, false},
+		{"null", `null`, `null`, true},
+		{"unknown format keeps the raw value", `"13/08/2021"`, `"13/08/2021"`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tm Time
+			if err := json.Unmarshal([]byte(tt.json), &tm); err != nil {
+				t.Fatal(err)
+			}
+			if tm.IsZero() != tt.zero {
+				t.Errorf("got zero time %v, want %v", tm.IsZero(), tt.zero)
+			}
+			b, err := json.Marshal(tm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+
+	// A Time built in code has no raw value and is sent as RFC 3339.
+	tm := Time{Time: time.Date(2021, 8, 14, 16, 0, 0, 0, time.UTC)}
+	if b, err := json.Marshal(tm); err != nil || string(b) != `"2021-08-14T16:00:00Z"` {
+		t.Errorf("got %s (error %v), want \"2021-08-14T16:00:00Z\"", b, err)
+	}
+}
+
+func TestKickoff(t *testing.T) {
+	date := func(raw string) Time {
+		tm, err := ParseTime(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	tests := []struct {
+		name    string
+		fixture Fixture
+		want    string
+	}{
+		{
+			name:    "fixture timezone",
+			fixture: Fixture{Date: date("2021-08-14T16:00:00+00:00"), Timezone: "Europe/London"},
+			want:    "2021-08-14T17:00:00+01:00",
+		},
+		{
+			name:    "offset sent by the api",
+			fixture: Fixture{Date: date("2021-08-14T13:00:00-03:00")},
+			want:    "2021-08-14T13:00:00-03:00",
+		},
+		{
+			name:    "unknown timezone",
+			fixture: Fixture{Date: date("2021-08-14T13:00:00-03:00"), Timezone: "Mars/Olympus"},
+			want:    "2021-08-14T13:00:00-03:00",
+		},
+		{
+			name:    "timestamp without a date",
+			fixture: Fixture{Timestamp: 1628956800, Timezone: "UTC"},
+			want:    "2021-08-14T16:00:00Z",
+		},
+		{
+			name:    "no date",
+			fixture: Fixture{},
+			want:    "0001-01-01T00:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fixture.Kickoff().Format(time.RFC3339); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	f := Fixture{Date: date("2021-08-14T16:00:00+00:00"), Timezone: "UTC"}
+	got, err := f.KickoffIn("America/Sao_Paulo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := got.Format(time.RFC3339); s != "2021-08-14T13:00:00-03:00" {
+		t.Errorf("got %s, want 2021-08-14T13:00:00-03:00", s)
+	}
+	if _, err := f.KickoffIn("Mars/Olympus"); err == nil {
+		t.Error("got no error for an unknown zone")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fball
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avalonbits/fball/object"
+)
+
+// ErrorKind classifies the error returned by a request.
+type ErrorKind string
+
+const (
+	ErrNone      ErrorKind = ""
+	ErrCanceled  ErrorKind = "canceled"
+	ErrTransport ErrorKind = "transport"
+	ErrDecode    ErrorKind = "decode"
+	ErrAPI       ErrorKind = "api"
+)
+
+// Quota is the request quota reported by api-football.com on every response. A value of -1
+// means the service did not report it.
+type Quota struct {
+	DailyLimit      int
+	DailyRemaining  int
+	MinuteLimit     int
+	MinuteRemaining int
+}
+
+var noQuota = Quota{-1, -1, -1, -1}
+
+func quotaFrom(h http.Header) Quota {
+	return Quota{
+		DailyLimit:      headerInt(h, "X-Ratelimit-Requests-Limit"),
+		DailyRemaining:  headerInt(h, "X-Ratelimit-Requests-Remaining"),
+		MinuteLimit:     headerInt(h, "X-Ratelimit-Limit"),
+		MinuteRemaining: headerInt(h, "X-Ratelimit-Remaining"),
+	}
+}
+
+func headerInt(h http.Header, key string) int {
+	v, err := strconv.Atoi(h.Get(key))
+	if err != nil {
+		return -1
+	}
+	return v
+}
+
+// Report describes a single call to one of the api-football.com endpoints.
+type Report struct {
+	// Endpoint is the path of the endpoint, e.g. "/fixtures".
+	Endpoint string
+
+	// Query is the url query string sent with the request.
+	Query string
+
+	// Duration is the time it took to get and decode the response.
+	Duration time.Duration
+
+	// Err is the error returned to the caller, if any, and ErrKind its classification.
+	Err     error
+	ErrKind ErrorKind
+
+	// Results and Paging are the result count and paging token of the response.
+	Results int
+	Paging  object.PagingToken
+
+	// CacheHit is true when the response was served without performing an http request.
+	CacheHit bool
+
+	// Attempts is the number of http requests performed to get the response.
+	Attempts int
+
+	// Quota is the quota left after the request. All values are -1 if no response was received.
+	Quota Quota
+}
+
+// Observer is an interface for receiving a Report for every request made by the client.
+// Observe is called synchronously, so implementations should be fast and safe for
+// concurrent use.
+type Observer interface {
+	Observe(ctx context.Context, r Report)
+}
+
+// StartObserver is an Observer that also needs to be notified before a request is made, e.g.
+// for starting a tracing span. The context returned by Start is the one later passed to
+// Observe. Start observers are called in registration order, each with the context returned
+// by the previous one, and the request is performed with the context returned by the last.
+type StartObserver interface {
+	Observer
+	Start(ctx context.Context, endpoint string) context.Context
+}
+
+func errorKind(ctx context.Context, err error, kind ErrorKind) ErrorKind {
+	if err == nil {
+		return ErrNone
+	}
+	if ctx.Err() != nil || isContextErr(err) {
+		return ErrCanceled
+	}
+	return kind
+}
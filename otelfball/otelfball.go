@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package otelfball provides OpenTelemetry tracing for fball.Client. Register it with
+// fball.WithObserver(otelfball.New(provider)) and every endpoint call produces a span that is
+// a child of the span found in the context passed to the client method.
+package otelfball
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/avalonbits/fball"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/avalonbits/fball/otelfball"
+
+// Tracer is a fball.StartObserver that records a span for every endpoint call.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// New creates a Tracer using tp. If tp is nil, the global tracer provider is used.
+func New(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Tracer{
+		tracer: tp.Tracer(instrumentationName),
+	}
+}
+
+// Start implements fball.StartObserver. It starts a span named after the endpoint.
+func (t *Tracer) Start(ctx context.Context, endpoint string) context.Context {
+	ctx, _ = t.tracer.Start(ctx, endpoint, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx
+}
+
+// Observe implements fball.Observer. It annotates and ends the span started by Start.
+func (t *Tracer) Observe(ctx context.Context, r fball.Report) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		span.End()
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("fball.endpoint", r.Endpoint),
+		attribute.Int("fball.results", r.Results),
+		attribute.Int("fball.paging.current", r.Paging.Current),
+		attribute.Int("fball.paging.total", r.Paging.Total),
+		attribute.Bool("fball.cache_hit", r.CacheHit),
+		attribute.Int("fball.attempts", r.Attempts),
+	}
+	if values, err := url.ParseQuery(r.Query); err == nil {
+		for k, v := range values {
+			attrs = append(attrs, attribute.String("fball.param."+k, strings.Join(v, ",")))
+		}
+	}
+	if r.Quota.DailyRemaining >= 0 {
+		attrs = append(attrs, attribute.Int("fball.quota.daily_remaining", r.Quota.DailyRemaining))
+	}
+	if r.Quota.MinuteRemaining >= 0 {
+		attrs = append(attrs, attribute.Int("fball.quota.minute_remaining", r.Quota.MinuteRemaining))
+	}
+	span.SetAttributes(attrs...)
+
+	if r.Err != nil {
+		span.SetAttributes(attribute.String("fball.error_kind", string(r.ErrKind)))
+		span.RecordError(r.Err)
+		span.SetStatus(codes.Error, r.Err.Error())
+	}
+	span.End()
+}
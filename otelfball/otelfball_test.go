@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package otelfball
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/avalonbits/fball"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const playerStatsBody = `{
+	"get": "fixtures/players",
+	"parameters": {"fixture": "169080"},
+	"errors": [],
+	"results": 2,
+	"paging": {"current": 1, "total": 1},
+	"response": [{"team": {"id": 1}}, {"team": {"id": 2}}]
+}`
+
+type fakeDoer struct{}
+
+func (fakeDoer) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(playerStatsBody)),
+	}, nil
+}
+
+func newProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exp := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)), exp
+}
+
+func attrs(s tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	m := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestTracerSpan(t *testing.T) {
+	tp, exp := newProvider()
+	client := fball.NewClient("key", fakeDoer{}, fball.WithObserver(New(tp)))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	if _, err := client.PlayerStats(ctx, fball.PlayerStatsParams{Fixture: "169080", Team: "33"}); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := exp.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "/fixtures/players" {
+		t.Errorf("span name: got %q, want %q", span.Name, "/fixtures/players")
+	}
+	if span.SpanKind != trace.SpanKindClient {
+		t.Errorf("span kind: got %v, want %v", span.SpanKind, trace.SpanKindClient)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("parent: got %v, want %v", span.Parent.SpanID(), parent.SpanContext().SpanID())
+	}
+	if span.SpanContext.TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("trace id: got %v, want %v", span.SpanContext.TraceID(), parent.SpanContext().TraceID())
+	}
+
+	got := attrs(span)
+	want := map[attribute.Key]attribute.Value{
+		"fball.endpoint":       attribute.StringValue("/fixtures/players"),
+		"fball.param.fixture":  attribute.StringValue("169080"),
+		"fball.param.team":     attribute.StringValue("33"),
+		"fball.results":        attribute.IntValue(2),
+		"fball.paging.current": attribute.IntValue(1),
+		"fball.paging.total":   attribute.IntValue(1),
+		"fball.cache_hit":      attribute.BoolValue(false),
+		"fball.attempts":       attribute.IntValue(1),
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("attribute %s: got %v, want %v", k, got[k].Emit(), v.Emit())
+		}
+	}
+	if _, ok := got["fball.error_kind"]; ok {
+		t.Errorf("unexpected fball.error_kind attribute on a successful call")
+	}
+}
+
+func TestTracerMultipleObservers(t *testing.T) {
+	tp1, exp1 := newProvider()
+	tp2, exp2 := newProvider()
+	client := fball.NewClient("key", fakeDoer{},
+		fball.WithObserver(New(tp1)), fball.WithObserver(New(tp2)))
+
+	if _, err := client.PlayerStats(context.Background(), fball.PlayerStatsParams{Fixture: "169080"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Spans are only exported when they end, so each tracer must have ended its own span.
+	for i, exp := range []*tracetest.InMemoryExporter{exp1, exp2} {
+		spans := exp.GetSpans()
+		if len(spans) != 1 {
+			t.Fatalf("tracer %d: got %d spans, want 1", i, len(spans))
+		}
+		if v := attrs(spans[0])["fball.results"]; v != attribute.IntValue(2) {
+			t.Errorf("tracer %d: fball.results: got %v, want 2", i, v.Emit())
+		}
+	}
+}
@@ -0,0 +1,370 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package poisson predicts match outcomes with a Poisson goals model, optionally with the
+// Dixon-Coles low score correction and time decay.
+package poisson
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/avalonbits/fball/object"
+)
+
+// Config configures how the model is fitted.
+type Config struct {
+	// DixonColes enables the Dixon-Coles correction for 0-0, 1-0, 0-1 and 1-1 scores.
+	DixonColes bool
+
+	// HalfLife is the age at which a fixture weighs half as much as one played at Now. Zero
+	// disables time decay. Fixtures played after Now weigh as much as one played at Now.
+	HalfLife time.Duration
+
+	// Now is the reference time for time decay. If zero, the kickoff of the most recent
+	// fixture is used.
+	Now time.Time
+
+	// MaxGoals is the highest number of goals per team in the scoreline matrix.
+	MaxGoals int
+
+	// Iterations is the maximum number of fitting iterations.
+	Iterations int
+}
+
+// DefaultConfig fits a Dixon-Coles model with a half life of half a season.
+func DefaultConfig() Config {
+	return Config{
+		DixonColes: true,
+		HalfLife:   180 * 24 * time.Hour,
+		MaxGoals:   10,
+		Iterations: 100,
+	}
+}
+
+// Model holds the fitted strengths of each team. Attack is how many goals a team scores and
+// Defence how many it concedes, relative to an average team, so a lower Defence is better.
+type Model struct {
+	Attack        map[int]float64
+	Defence       map[int]float64
+	HomeAdvantage float64
+	Rho           float64
+
+	maxGoals int
+}
+
+type result struct {
+	home, away int
+	hg, ag     int
+	weight     float64
+}
+
+// Fit fits the model to the finished fixtures, usually a season returned by
+// Client.FixtureInfo.
+func Fit(fixtures []object.FixtureDetail, cfg Config) (*Model, error) {
+	if cfg.MaxGoals <= 0 {
+		cfg.MaxGoals = 10
+	}
+	if cfg.Iterations <= 0 {
+		cfg.Iterations = 100
+	}
+
+	now := cfg.Now
+	if now.IsZero() {
+		for _, f := range fixtures {
+			if k := f.Fixture.Kickoff(); f.Fixture.Status.Short.IsPlayed() && k.After(now) {
+				now = k
+			}
+		}
+	}
+
+	results := []result{}
+	m := &Model{
+		Attack:        map[int]float64{},
+		Defence:       map[int]float64{},
+		HomeAdvantage: 1,
+		maxGoals:      cfg.MaxGoals,
+	}
+	for _, f := range fixtures {
+		if !f.Fixture.Status.Short.IsPlayed() {
+			continue
+		}
+		results = append(results, result{
+			home:   f.Teams.Home.ID,
+			away:   f.Teams.Away.ID,
+			hg:     f.Goals.Home,
+			ag:     f.Goals.Away,
+			weight: decay(now.Sub(f.Fixture.Kickoff()), cfg.HalfLife),
+		})
+		m.Attack[f.Teams.Home.ID] = 1
+		m.Attack[f.Teams.Away.ID] = 1
+		m.Defence[f.Teams.Home.ID] = 1
+		m.Defence[f.Teams.Away.ID] = 1
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no finished fixtures to fit")
+	}
+
+	// Maximum likelihood estimation of the independent Poisson model by iteratively solving
+	// the likelihood equations of each parameter given the others.
+	for it := 0; it < cfg.Iterations; it++ {
+		scored, against := map[int]float64{}, map[int]float64{}
+		expScored, expAgainst := map[int]float64{}, map[int]float64{}
+		homeGoals, expHome := 0.0, 0.0
+		for _, r := range results {
+			scored[r.home] += r.weight * float64(r.hg)
+			scored[r.away] += r.weight * float64(r.ag)
+			expScored[r.home] += r.weight * m.HomeAdvantage * m.Defence[r.away]
+			expScored[r.away] += r.weight * m.Defence[r.home]
+		}
+		change := 0.0
+		for team := range m.Attack {
+			a := safeDiv(scored[team], expScored[team])
+			change = math.Max(change, math.Abs(a-m.Attack[team]))
+			m.Attack[team] = a
+		}
+
+		for _, r := range results {
+			against[r.home] += r.weight * float64(r.ag)
+			against[r.away] += r.weight * float64(r.hg)
+			expAgainst[r.home] += r.weight * m.Attack[r.away]
+			expAgainst[r.away] += r.weight * m.HomeAdvantage * m.Attack[r.home]
+		}
+		for team := range m.Defence {
+			d := safeDiv(against[team], expAgainst[team])
+			change = math.Max(change, math.Abs(d-m.Defence[team]))
+			m.Defence[team] = d
+		}
+
+		for _, r := range results {
+			homeGoals += r.weight * float64(r.hg)
+			expHome += r.weight * m.Attack[r.home] * m.Defence[r.away]
+		}
+		if h := safeDiv(homeGoals, expHome); h > 0 {
+			change = math.Max(change, math.Abs(h-m.HomeAdvantage))
+			m.HomeAdvantage = h
+		}
+
+		m.normalize()
+		if change < 1e-6 {
+			break
+		}
+	}
+
+	if cfg.DixonColes {
+		m.Rho = fitRho(m, results)
+	}
+	return m, nil
+}
+
+// decay returns the weight of a fixture played age ago. Fixtures played after the reference
+// time weigh as much as one played at it.
+func decay(age, halfLife time.Duration) float64 {
+	if halfLife <= 0 {
+		return 1
+	}
+	age = max(age, 0)
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// normalize scales the strengths so that the average attack is 1. The expected goals of every
+// fixture are unchanged.
+func (m *Model) normalize() {
+	mean := 0.0
+	for _, a := range m.Attack {
+		mean += a
+	}
+	mean /= float64(len(m.Attack))
+	if mean == 0 {
+		return
+	}
+	for team := range m.Attack {
+		m.Attack[team] /= mean
+		m.Defence[team] *= mean
+	}
+}
+
+// fitRho finds the Dixon-Coles rho that maximizes the likelihood of the low scores, holding the
+// team strengths fixed, with a golden section search.
+func fitRho(m *Model, results []result) float64 {
+	loglik := func(rho float64) float64 {
+		ll := 0.0
+		for _, r := range results {
+			lh, la := m.lambdas(r.home, r.away)
+			t := tau(r.hg, r.ag, lh, la, rho)
+			if t <= 0 {
+				return math.Inf(-1)
+			}
+			ll += r.weight * math.Log(t)
+		}
+		return ll
+	}
+
+	lo, hi := -0.3, 0.3
+	g := (math.Sqrt(5) - 1) / 2
+	for i := 0; i < 60; i++ {
+		a := hi - g*(hi-lo)
+		b := lo + g*(hi-lo)
+		if loglik(a) > loglik(b) {
+			hi = b
+		} else {
+			lo = a
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// tau is the Dixon-Coles correction factor.
+func tau(hg, ag int, lh, la, rho float64) float64 {
+	switch {
+	case hg == 0 && ag == 0:
+		return 1 - lh*la*rho
+	case hg == 0 && ag == 1:
+		return 1 + lh*rho
+	case hg == 1 && ag == 0:
+		return 1 + la*rho
+	case hg == 1 && ag == 1:
+		return 1 - rho
+	}
+	return 1
+}
+
+func safeDiv(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+func (m *Model) lambdas(home, away int) (float64, float64) {
+	return m.HomeAdvantage * m.Attack[home] * m.Defence[away], m.Attack[away] * m.Defence[home]
+}
+
+// ExpectedGoals returns the expected goals of each team in a fixture between home and away.
+func (m *Model) ExpectedGoals(home, away int) (float64, float64, error) {
+	if _, ok := m.Attack[home]; !ok {
+		return 0, 0, fmt.Errorf("unknown team %d", home)
+	}
+	if _, ok := m.Attack[away]; !ok {
+		return 0, 0, fmt.Errorf("unknown team %d", away)
+	}
+	lh, la := m.lambdas(home, away)
+	return lh, la, nil
+}
+
+// Scorelines returns the scoreline probabilities of a fixture between home and away.
+func (m *Model) Scorelines(home, away int) (Matrix, error) {
+	lh, la, err := m.ExpectedGoals(home, away)
+	if err != nil {
+		return nil, err
+	}
+	return NewMatrix(lh, la, m.Rho, m.maxGoals), nil
+}
+
+// Matrix holds scoreline probabilities, indexed by home goals and then away goals.
+type Matrix [][]float64
+
+// NewMatrix returns the scoreline probabilities for teams expected to score homeGoals and
+// awayGoals, with Dixon-Coles correction rho (0 for none), up to maxGoals per team. The
+// probabilities are normalized to add up to 1.
+func NewMatrix(homeGoals, awayGoals, rho float64, maxGoals int) Matrix {
+	m := make(Matrix, maxGoals+1)
+	total := 0.0
+	for h := 0; h <= maxGoals; h++ {
+		m[h] = make([]float64, maxGoals+1)
+		for a := 0; a <= maxGoals; a++ {
+			p := pmf(h, homeGoals) * pmf(a, awayGoals) * math.Max(tau(h, a, homeGoals, awayGoals, rho), 0)
+			m[h][a] = p
+			total += p
+		}
+	}
+	if total > 0 {
+		for h := range m {
+			for a := range m[h] {
+				m[h][a] /= total
+			}
+		}
+	}
+	return m
+}
+
+func pmf(k int, lambda float64) float64 {
+	lg, _ := math.Lgamma(float64(k + 1))
+	if lambda == 0 {
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}
+	return math.Exp(float64(k)*math.Log(lambda) - lambda - lg)
+}
+
+// Outcome returns the probabilities of a home win, a draw and an away win.
+func (m Matrix) Outcome() (home, draw, away float64) {
+	for h := range m {
+		for a, p := range m[h] {
+			switch {
+			case h > a:
+				home += p
+			case h == a:
+				draw += p
+			default:
+				away += p
+			}
+		}
+	}
+	return home, draw, away
+}
+
+// Over returns the probability of more than line total goals, e.g. 2.5.
+func (m Matrix) Over(line float64) float64 {
+	over := 0.0
+	for h := range m {
+		for a, p := range m[h] {
+			if float64(h+a) > line {
+				over += p
+			}
+		}
+	}
+	return over
+}
+
+// Under returns the probability of fewer than line total goals.
+func (m Matrix) Under(line float64) float64 {
+	under := 0.0
+	for h := range m {
+		for a, p := range m[h] {
+			if float64(h+a) < line {
+				under += p
+			}
+		}
+	}
+	return under
+}
+
+// BothTeamsScore returns the probability of both teams scoring.
+func (m Matrix) BothTeamsScore() float64 {
+	btts := 0.0
+	for h := 1; h < len(m); h++ {
+		for a := 1; a < len(m[h]); a++ {
+			btts += m[h][a]
+		}
+	}
+	return btts
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package poisson
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/avalonbits/fball/object"
+)
+
+func fixture(kickoff time.Time, home, away, hg, ag int) object.FixtureDetail {
+	f := object.FixtureDetail{}
+	f.Fixture.Timestamp = kickoff.Unix()
+	f.Fixture.Status.Short = object.StatusFinished
+	f.Teams.Home.ID, f.Teams.Away.ID = home, away
+	f.Goals.Home, f.Goals.Away = hg, ag
+	return f
+}
+
+// sample draws a scoreline from m.
+func sample(rng *rand.Rand, m Matrix) (int, int) {
+	x := rng.Float64()
+	for h := range m {
+		for a, p := range m[h] {
+			if x -= p; x < 0 {
+				return h, a
+			}
+		}
+	}
+	return len(m) - 1, len(m) - 1
+}
+
+func TestFitRecoversParameters(t *testing.T) {
+	truth := &Model{
+		Attack:        map[int]float64{1: 1.5, 2: 1.2, 3: 1.0, 4: 0.8, 5: 0.5},
+		Defence:       map[int]float64{1: 0.6, 2: 0.8, 3: 1.0, 4: 1.2, 5: 1.5},
+		HomeAdvantage: 1.3,
+		Rho:           -0.1,
+		maxGoals:      10,
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	kickoff := time.Date(2021, 8, 1, 15, 0, 0, 0, time.UTC)
+	fixtures := []object.FixtureDetail{}
+	teams := []int{1, 2, 3, 4, 5}
+	for round := 0; round < 200; round++ {
+		for _, home := range teams {
+			for _, away := range teams {
+				if home == away {
+					continue
+				}
+				m, err := truth.Scorelines(home, away)
+				if err != nil {
+					t.Fatal(err)
+				}
+				hg, ag := sample(rng, m)
+				fixtures = append(fixtures, fixture(kickoff, home, away, hg, ag))
+			}
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.HalfLife = 0
+	m, err := Fit(fixtures, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Strengths are normalized to an average attack of 1, as in truth.
+	const tolerance = 0.1
+	for team := range truth.Attack {
+		if d := math.Abs(m.Attack[team] - truth.Attack[team]); d > tolerance {
+			t.Errorf("team %d: got attack %.3f, want %.3f", team, m.Attack[team], truth.Attack[team])
+		}
+		if d := math.Abs(m.Defence[team] - truth.Defence[team]); d > tolerance {
+			t.Errorf("team %d: got defence %.3f, want %.3f", team, m.Defence[team], truth.Defence[team])
+		}
+	}
+	if d := math.Abs(m.HomeAdvantage - truth.HomeAdvantage); d > tolerance {
+		t.Errorf("got home advantage %.3f, want %.3f", m.HomeAdvantage, truth.HomeAdvantage)
+	}
+	if d := math.Abs(m.Rho - truth.Rho); d > 0.05 {
+		t.Errorf("got rho %.3f, want %.3f", m.Rho, truth.Rho)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	tests := []struct {
+		name       string
+		home, away float64
+		rho        float64
+	}{
+		{"poisson", 1.6, 1.1, 0},
+		{"dixon-coles", 1.6, 1.1, -0.13},
+		{"low scoring", 0.4, 0.3, 0.1},
+		{"high scoring", 3.5, 2.8, -0.05},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatrix(tt.home, tt.away, tt.rho, 10)
+			homeMass := 0.0
+			for h := range m {
+				homeMass += pmf(h, tt.home)
+			}
+
+			total := 0.0
+			for h := range m {
+				row := 0.0
+				for _, p := range m[h] {
+					row += p
+				}
+				total += row
+
+				// The Dixon-Coles correction keeps the marginal distribution of each team's
+				// goals, so each row adds up to the chance of the home team scoring h goals,
+				// given that it scores at most maxGoals.
+				if want := pmf(h, tt.home) / homeMass; math.Abs(row-want) > 1e-9 {
+					t.Errorf("row %d: got %.8f, want %.8f", h, row, want)
+				}
+			}
+			if math.Abs(total-1) > 1e-9 {
+				t.Errorf("got total probability %.12f, want 1", total)
+			}
+
+			home, draw, away := m.Outcome()
+			if math.Abs(home+draw+away-1) > 1e-9 {
+				t.Errorf("got outcome probabilities adding up to %.12f, want 1", home+draw+away)
+			}
+			for _, line := range []float64{0.5, 1.5, 2.5, 3.5, 4.5} {
+				if sum := m.Over(line) + m.Under(line); math.Abs(sum-1) > 1e-9 {
+					t.Errorf("line %.1f: got over + under = %.12f, want 1", line, sum)
+				}
+			}
+		})
+	}
+}
+
+func TestDecay(t *testing.T) {
+	const day = 24 * time.Hour
+	tests := []struct {
+		age, halfLife time.Duration
+		want          float64
+	}{
+		{0, 0, 1},
+		{100 * day, 0, 1},
+		{0, 10 * day, 1},
+		{10 * day, 10 * day, 0.5},
+		{20 * day, 10 * day, 0.25},
+		{-10 * day, 10 * day, 1},
+	}
+	for _, tt := range tests {
+		if got := decay(tt.age, tt.halfLife); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("decay(%v, %v): got %v, want %v", tt.age, tt.halfLife, got, tt.want)
+		}
+	}
+}
+
+func TestFitFixturesAfterNow(t *testing.T) {
+	now := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	results := [][4]int{{1, 2, 2, 0}, {2, 3, 1, 1}, {3, 1, 0, 3}, {2, 1, 1, 2}, {3, 2, 2, 1}, {1, 3, 1, 0}}
+
+	atNow, afterNow := []object.FixtureDetail{}, []object.FixtureDetail{}
+	for i, r := range results {
+		atNow = append(atNow, fixture(now, r[0], r[1], r[2], r[3]))
+		later := now.Add(time.Duration(i+1) * 30 * 24 * time.Hour)
+		afterNow = append(afterNow, fixture(later, r[0], r[1], r[2], r[3]))
+	}
+
+	cfg := DefaultConfig()
+	cfg.Now = now
+	want, err := Fit(atNow, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Fit(afterNow, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for team := range want.Attack {
+		if math.Abs(got.Attack[team]-want.Attack[team]) > 1e-9 {
+			t.Errorf("team %d: got attack %v, want %v", team, got.Attack[team], want.Attack[team])
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package simulate
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/avalonbits/fball/elo"
+	"github.com/avalonbits/fball/poisson"
+)
+
+// MatchModel plays a fixture. Implementations must only use rng as their source of randomness
+// so that simulations are deterministic.
+type MatchModel interface {
+	Play(rng *rand.Rand, home, away int) (homeGoals, awayGoals int, err error)
+}
+
+// PoissonModel plays fixtures by sampling the scoreline probabilities of a poisson.Model. It
+// caches the scoreline matrices and is not safe for concurrent use.
+type PoissonModel struct {
+	Model *poisson.Model
+
+	matrices map[[2]int]poisson.Matrix
+}
+
+// NewPoissonModel creates a PoissonModel from m.
+func NewPoissonModel(m *poisson.Model) *PoissonModel {
+	return &PoissonModel{
+		Model:    m,
+		matrices: map[[2]int]poisson.Matrix{},
+	}
+}
+
+func (pm *PoissonModel) Play(rng *rand.Rand, home, away int) (int, int, error) {
+	key := [2]int{home, away}
+	m, ok := pm.matrices[key]
+	if !ok {
+		var err error
+		if m, err = pm.Model.Scorelines(home, away); err != nil {
+			return 0, 0, err
+		}
+		pm.matrices[key] = m
+	}
+
+	x := rng.Float64()
+	for h := range m {
+		for a, p := range m[h] {
+			if x -= p; x < 0 {
+				return h, a, nil
+			}
+		}
+	}
+	last := len(m) - 1
+	return last, last, nil
+}
+
+// EloModel plays fixtures by sampling the result probabilities of elo.Ratings. Elo ratings
+// predict results but not goals, so the margin of victory is drawn from a fixed distribution
+// that resembles a typical league.
+type EloModel struct {
+	Ratings *elo.Ratings
+}
+
+func (em EloModel) Play(rng *rand.Rand, home, away int) (int, int, error) {
+	p := em.Ratings.Predict(home, away)
+	x := rng.Float64()
+	switch {
+	case x < p.Home:
+		loser := samplePoisson(rng, 0.7)
+		return loser + 1 + samplePoisson(rng, 0.5), loser, nil
+	case x < p.Home+p.Draw:
+		goals := samplePoisson(rng, 0.9)
+		return goals, goals, nil
+	}
+	loser := samplePoisson(rng, 0.7)
+	return loser, loser + 1 + samplePoisson(rng, 0.5), nil
+}
+
+func samplePoisson(rng *rand.Rand, lambda float64) int {
+	limit := math.Exp(-lambda)
+	k, p := 0, rng.Float64()
+	for p > limit {
+		k++
+		p *= rng.Float64()
+	}
+	return k
+}
@@ -0,0 +1,211 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package simulate plays out the rest of a season many times to estimate the odds of each
+// final position.
+package simulate
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/avalonbits/fball/object"
+	"github.com/avalonbits/fball/standings"
+)
+
+// Config configures a simulation.
+type Config struct {
+	// Runs is the number of times the season is played out.
+	Runs int
+
+	// Seed makes the simulation deterministic: the same inputs and seed give the same odds.
+	Seed int64
+
+	// Model plays each remaining fixture.
+	Model MatchModel
+
+	// Standings has the points for each result and the tiebreakers. If Win, Draw and Loss are
+	// all 0, the points of standings.DefaultConfig are used, along with its tiebreakers if none
+	// are set. Only GoalDifference, GoalsFor and AwayGoals can be applied to simulated tables:
+	// other tiebreakers, like the HeadToHead of the default config, are skipped. Teams still
+	// tied are ordered at random.
+	Standings standings.Config
+}
+
+// TeamOdds are the simulated final position odds of a team.
+type TeamOdds struct {
+	Team object.TeamData
+
+	// Positions has the probability of each final position: Positions[0] is the chance of
+	// finishing first.
+	Positions []float64
+
+	// Zones has the probability of finishing in each zone of the table, keyed by the
+	// Ranking.Description of the positions in the zone, e.g. "Relegation".
+	Zones map[string]float64
+
+	// ExpectedPoints is the average final number of points.
+	ExpectedPoints float64
+}
+
+type simTeam struct {
+	id        int
+	points    int
+	goalsFor  int
+	goalsDiff int
+	awayGoals int
+	tiebreak  float64
+}
+
+// Run simulates the remaining fixtures starting from the current table, e.g. the rankings of a
+// group from Client.Standings and the fixtures from Client.FixtureInfo with Status "NS".
+// Fixtures that already have a final result or were cancelled are ignored. The returned odds
+// are in the order of the current table.
+func Run(current []object.Ranking, remaining []object.FixtureDetail, cfg Config) ([]TeamOdds, error) {
+	if cfg.Model == nil {
+		return nil, fmt.Errorf("no match model")
+	}
+	if cfg.Runs <= 0 {
+		return nil, fmt.Errorf("invalid number of runs: %d", cfg.Runs)
+	}
+	points := cfg.Standings
+	if points.Win == 0 && points.Draw == 0 && points.Loss == 0 {
+		def := standings.DefaultConfig()
+		points.Win, points.Draw, points.Loss = def.Win, def.Draw, def.Loss
+		if len(points.Tiebreakers) == 0 {
+			points.Tiebreakers = def.Tiebreakers
+		}
+	}
+
+	table := append([]object.Ranking(nil), current...)
+	sort.SliceStable(table, func(i, j int) bool {
+		return table[i].Rank < table[j].Rank
+	})
+	index := make(map[int]int, len(table))
+	odds := make([]TeamOdds, len(table))
+	for i, r := range table {
+		index[r.Team.ID] = i
+		odds[i] = TeamOdds{
+			Team:      r.Team,
+			Positions: make([]float64, len(table)),
+			Zones:     map[string]float64{},
+		}
+	}
+
+	fixtures := []object.FixtureDetail{}
+	for _, f := range remaining {
+		if status := f.Fixture.Status.Short; status.IsFinished() || status.IsCancelled() {
+			continue
+		}
+		for _, id := range []int{f.Teams.Home.ID, f.Teams.Away.ID} {
+			if _, ok := index[id]; !ok {
+				return nil, fmt.Errorf("fixture %d: team %d is not in the table", f.Fixture.ID, id)
+			}
+		}
+		fixtures = append(fixtures, f)
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	teams := make([]simTeam, len(table))
+	for run := 0; run < cfg.Runs; run++ {
+		for i, r := range table {
+			teams[i] = simTeam{
+				id:        r.Team.ID,
+				points:    r.Points,
+				goalsFor:  r.All.Goals.For,
+				goalsDiff: r.All.Goals.For - r.All.Goals.Against,
+				awayGoals: r.Away.Goals.For,
+			}
+		}
+
+		for _, f := range fixtures {
+			hg, ag, err := cfg.Model.Play(rng, f.Teams.Home.ID, f.Teams.Away.ID)
+			if err != nil {
+				return nil, fmt.Errorf("fixture %d: %w", f.Fixture.ID, err)
+			}
+			home, away := &teams[index[f.Teams.Home.ID]], &teams[index[f.Teams.Away.ID]]
+			home.add(points, hg, ag)
+			away.add(points, ag, hg)
+			away.awayGoals += ag
+		}
+
+		final := append([]simTeam(nil), teams...)
+		for i := range final {
+			final[i].tiebreak = rng.Float64()
+		}
+		sort.Slice(final, func(i, j int) bool {
+			return better(final[i], final[j], points.Tiebreakers)
+		})
+		for pos, t := range final {
+			o := &odds[index[t.id]]
+			o.Positions[pos]++
+			o.ExpectedPoints += float64(t.points)
+		}
+	}
+
+	runs := float64(cfg.Runs)
+	for i := range odds {
+		o := &odds[i]
+		o.ExpectedPoints /= runs
+		for pos := range o.Positions {
+			o.Positions[pos] /= runs
+			if zone := table[pos].Description; zone != "" {
+				o.Zones[zone] += o.Positions[pos]
+			}
+		}
+	}
+	return odds, nil
+}
+
+func (t *simTeam) add(cfg standings.Config, scored, conceded int) {
+	t.goalsFor += scored
+	t.goalsDiff += scored - conceded
+	switch {
+	case scored > conceded:
+		t.points += cfg.Win
+	case scored == conceded:
+		t.points += cfg.Draw
+	default:
+		t.points += cfg.Loss
+	}
+}
+
+// better returns true if a finishes ahead of b.
+func better(a, b simTeam, tiebreakers []standings.Tiebreaker) bool {
+	if a.points != b.points {
+		return a.points > b.points
+	}
+	for _, tb := range tiebreakers {
+		switch tb {
+		case standings.GoalDifference:
+			if a.goalsDiff != b.goalsDiff {
+				return a.goalsDiff > b.goalsDiff
+			}
+		case standings.GoalsFor:
+			if a.goalsFor != b.goalsFor {
+				return a.goalsFor > b.goalsFor
+			}
+		case standings.AwayGoals:
+			if a.awayGoals != b.awayGoals {
+				return a.awayGoals > b.awayGoals
+			}
+		}
+	}
+	return a.tiebreak < b.tiebreak
+}
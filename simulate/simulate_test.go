@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package simulate
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/avalonbits/fball/elo"
+	"github.com/avalonbits/fball/object"
+	"github.com/avalonbits/fball/poisson"
+	"github.com/avalonbits/fball/standings"
+)
+
+func fixture(id, home, away int, status object.FixtureStatus, hg, ag int) object.FixtureDetail {
+	f := object.FixtureDetail{}
+	f.Fixture.ID = id
+	f.Fixture.Timestamp = time.Date(2021, 8, 1, 15, 0, 0, 0, time.UTC).Unix() + int64(id)*7*24*3600
+	f.Fixture.Status.Short = status
+	f.League.Round = "Regular Season - 1"
+	f.Teams.Home.ID, f.Teams.Away.ID = home, away
+	f.Goals.Home, f.Goals.Away = hg, ag
+	return f
+}
+
+// played is the first half of a season between four teams and remaining the second half.
+var (
+	played = []object.FixtureDetail{
+		fixture(1, 1, 2, object.StatusFinished, 2, 1),
+		fixture(2, 3, 4, object.StatusFinished, 1, 1),
+		fixture(3, 1, 3, object.StatusFinished, 3, 1),
+		fixture(4, 2, 4, object.StatusFinished, 2, 0),
+		fixture(5, 4, 1, object.StatusFinished, 1, 2),
+		fixture(6, 2, 3, object.StatusFinished, 1, 0),
+	}
+	remaining = []object.FixtureDetail{
+		fixture(7, 2, 1, object.StatusNotStarted, 0, 0),
+		fixture(8, 4, 3, object.StatusNotStarted, 0, 0),
+		fixture(9, 3, 1, object.StatusNotStarted, 0, 0),
+		fixture(10, 4, 2, object.StatusNotStarted, 0, 0),
+		fixture(11, 1, 4, object.StatusNotStarted, 0, 0),
+		fixture(12, 3, 2, object.StatusNotStarted, 0, 0),
+	}
+)
+
+func table(t *testing.T) []object.Ranking {
+	t.Helper()
+	tables := standings.Build(played, standings.DefaultConfig())
+	if len(tables) != 1 {
+		t.Fatalf("got %d tables, want 1", len(tables))
+	}
+	return tables[0].Rankings
+}
+
+// models returns constructors of each match model, fitted to the played fixtures. Models are
+// created anew for each run so that no state is shared between them.
+func models(t *testing.T) map[string]func() MatchModel {
+	t.Helper()
+	pm, err := poisson.Fit(played, poisson.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ratings := elo.New(elo.DefaultConfig())
+	ratings.Update(played)
+
+	return map[string]func() MatchModel{
+		"poisson": func() MatchModel { return NewPoissonModel(pm) },
+		"elo":     func() MatchModel { return EloModel{Ratings: ratings} },
+	}
+}
+
+func TestRunDeterministic(t *testing.T) {
+	current := table(t)
+	for name, model := range models(t) {
+		t.Run(name, func(t *testing.T) {
+			run := func(seed int64) []TeamOdds {
+				odds, err := Run(current, remaining, Config{
+					Runs:      2000,
+					Seed:      seed,
+					Model:     model(),
+					Standings: standings.DefaultConfig(),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return odds
+			}
+
+			odds := run(7)
+			if again := run(7); !reflect.DeepEqual(odds, again) {
+				t.Errorf("got different odds for the same seed:\n%+v\n%+v", odds, again)
+			}
+			if other := run(8); reflect.DeepEqual(odds, other) {
+				t.Errorf("got the same odds for different seeds")
+			}
+
+			positions := make([]float64, len(current))
+			for i, o := range odds {
+				total := 0.0
+				for pos, p := range o.Positions {
+					total += p
+					positions[pos] += p
+				}
+				if math.Abs(total-1) > 1e-9 {
+					t.Errorf("team %d: got positions adding up to %v, want 1", o.Team.ID, total)
+				}
+				if pts := float64(current[i].Points); o.ExpectedPoints < pts || o.ExpectedPoints > pts+9 {
+					t.Errorf("team %d: got %.2f expected points, want between %v and %v", o.Team.ID, o.ExpectedPoints, pts, pts+9)
+				}
+			}
+			for pos, p := range positions {
+				if math.Abs(p-1) > 1e-9 {
+					t.Errorf("position %d: got odds adding up to %v, want 1", pos+1, p)
+				}
+			}
+		})
+	}
+}
+
+func TestRunDefaultPoints(t *testing.T) {
+	current := table(t)
+	model := models(t)["elo"]
+	run := func(points standings.Config) []TeamOdds {
+		odds, err := Run(current, remaining, Config{Runs: 500, Seed: 1, Model: model(), Standings: points})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return odds
+	}
+
+	odds := run(standings.Config{})
+	if want := run(standings.DefaultConfig()); !reflect.DeepEqual(odds, want) {
+		t.Errorf("got odds %+v for the zero config, want the ones of the default config %+v", odds, want)
+	}
+	if odds[0].ExpectedPoints <= float64(current[0].Points) {
+		t.Errorf("got %.2f expected points for the leader, want more than %d", odds[0].ExpectedPoints, current[0].Points)
+	}
+}
+
+func TestRunSkipsFinishedAndCancelled(t *testing.T) {
+	current := table(t)
+	model := models(t)["poisson"]
+	run := func(fixtures []object.FixtureDetail) []TeamOdds {
+		odds, err := Run(current, fixtures, Config{Runs: 500, Seed: 1, Model: model(), Standings: standings.DefaultConfig()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return odds
+	}
+
+	// Teams 5 and 6 are not in the table, which fails the run unless the fixture is skipped.
+	withSkipped := append([]object.FixtureDetail{
+		fixture(13, 1, 2, object.StatusCancelled, 0, 0),
+		fixture(14, 3, 4, object.StatusAbandoned, 0, 0),
+		fixture(15, 5, 6, object.StatusCancelled, 0, 0),
+	}, played...)
+	withSkipped = append(withSkipped, remaining...)
+
+	if got, want := run(withSkipped), run(remaining); !reflect.DeepEqual(got, want) {
+		t.Errorf("got odds %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package standings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avalonbits/fball"
+	"github.com/avalonbits/fball/object"
+)
+
+// Season holds the rounds, in order, and the fixtures of a league season.
+type Season struct {
+	Rounds   []string
+	Fixtures []object.FixtureDetail
+}
+
+// LoadSeason fetches the rounds and fixtures of a league season.
+func LoadSeason(ctx context.Context, c *fball.Client, league, season string) (Season, error) {
+	rr, err := c.Round(ctx, fball.RoundParams{League: league, Season: season})
+	if err != nil {
+		return Season{}, err
+	}
+	fir, err := c.FixtureInfo(ctx, fball.FixtureInfoParams{League: league, Season: season})
+	if err != nil {
+		return Season{}, err
+	}
+	return Season{
+		Rounds:   rr.Rounds,
+		Fixtures: fir.FixtureInfo,
+	}, nil
+}
+
+// AfterRound returns the tables after all fixtures of round and of the rounds before it were
+// played. Fixtures that were postponed and played later are counted in their own round.
+func (s Season) AfterRound(round string, cfg Config) ([]Table, error) {
+	rounds, err := s.roundsUntil(round)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := []object.FixtureDetail{}
+	for _, f := range s.Fixtures {
+		if rounds[f.League.Round] {
+			fixtures = append(fixtures, f)
+		}
+	}
+	return Build(fixtures, cfg), nil
+}
+
+// OnDate returns the tables with the fixtures that kicked off before t.
+func (s Season) OnDate(t time.Time, cfg Config) []Table {
+	fixtures := []object.FixtureDetail{}
+	for _, f := range s.Fixtures {
+		if f.Fixture.Kickoff().Before(t) {
+			fixtures = append(fixtures, f)
+		}
+	}
+	return Build(fixtures, cfg)
+}
+
+func (s Season) roundsUntil(round string) (map[string]bool, error) {
+	rounds := map[string]bool{}
+	for _, r := range s.Rounds {
+		rounds[r] = true
+		if r == round {
+			return rounds, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown round %q", round)
+}
+
+// Position is the place of a team in its group table after a round.
+type Position struct {
+	Round  string
+	Group  string
+	Rank   int
+	Points int
+}
+
+// PositionHistory returns, for each team id, its position after each round in which it had
+// played at least once.
+func (s Season) PositionHistory(cfg Config) (map[int][]Position, error) {
+	history := map[int][]Position{}
+	for _, round := range s.Rounds {
+		tables, err := s.AfterRound(round, cfg)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tables {
+			for _, r := range t.Rankings {
+				history[r.Team.ID] = append(history[r.Team.ID], Position{
+					Round:  round,
+					Group:  t.Group,
+					Rank:   r.Rank,
+					Points: r.Points,
+				})
+			}
+		}
+	}
+	return history, nil
+}
@@ -0,0 +1,339 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package standings computes league tables from fixture results. The tables use the same
+// object.Ranking type returned by the standings endpoint, so they can be compared with it.
+package standings
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/avalonbits/fball/object"
+)
+
+// Tiebreaker decides the order of teams with the same number of points.
+type Tiebreaker int
+
+const (
+	// GoalDifference ranks the team with the best overall goal difference first.
+	GoalDifference Tiebreaker = iota
+	// GoalsFor ranks the team that scored the most goals first.
+	GoalsFor
+	// HeadToHead ranks the tied teams by the points, then goal difference, then goals scored
+	// in the fixtures played among them.
+	HeadToHead
+	// AwayGoals ranks the team that scored the most away goals first.
+	AwayGoals
+	// FairPlay ranks the team with the fewest disciplinary points first, on the FIFA scale: 1
+	// for a yellow card, 3 for a player sent off with two yellows, 4 for a straight red and 5
+	// for a yellow followed by a straight red. It needs the fixture events.
+	FairPlay
+)
+
+// Config configures how tables are computed.
+type Config struct {
+	// Points awarded for each result.
+	Win  int
+	Draw int
+	Loss int
+
+	// Tiebreakers are applied in order to teams with the same number of points. Teams still
+	// tied after all of them are ordered by name.
+	Tiebreakers []Tiebreaker
+
+	// FormLength is the number of results in the form string.
+	FormLength int
+
+	// Group returns the group of a fixture. If nil, the group is parsed from the round name,
+	// so "Group A - 3" is in "Group A", and fixtures from rounds without a group go to a
+	// single table.
+	Group func(object.FixtureDetail) string
+}
+
+// DefaultConfig awards 3 points for a win and 1 for a draw and breaks ties by goal difference,
+// goals scored and head to head results.
+func DefaultConfig() Config {
+	return Config{
+		Win:         3,
+		Draw:        1,
+		Loss:        0,
+		Tiebreakers: []Tiebreaker{GoalDifference, GoalsFor, HeadToHead},
+		FormLength:  5,
+	}
+}
+
+// Table is the ranking of a group. Group is empty for leagues without groups.
+type Table struct {
+	Group    string
+	Rankings []object.Ranking
+}
+
+// GroupFromRound returns the group of a round name like "Group A - 3", or "" if the round is
+// not part of a group stage.
+func GroupFromRound(round string) string {
+	if !strings.HasPrefix(round, "Group") {
+		return ""
+	}
+	group, _, _ := strings.Cut(round, " - ")
+	return strings.TrimSpace(group)
+}
+
+type teamRecord struct {
+	ranking  object.Ranking
+	results  []byte
+	fairPlay int
+}
+
+// Build computes the tables from the fixtures. Only fixtures with a final result are used and
+// tables are returned ordered by group name.
+func Build(fixtures []object.FixtureDetail, cfg Config) []Table {
+	group := cfg.Group
+	if group == nil {
+		group = func(f object.FixtureDetail) string {
+			return GroupFromRound(f.League.Round)
+		}
+	}
+
+	played := make([]object.FixtureDetail, 0, len(fixtures))
+	for _, f := range fixtures {
+		if f.Fixture.Status.Short.IsFinished() {
+			played = append(played, f)
+		}
+	}
+	sort.SliceStable(played, func(i, j int) bool {
+		return played[i].Fixture.Timestamp < played[j].Fixture.Timestamp
+	})
+
+	groups := map[string]map[int]*teamRecord{}
+	groupFixtures := map[string][]object.FixtureDetail{}
+	for _, f := range played {
+		g := group(f)
+		records, ok := groups[g]
+		if !ok {
+			records = map[int]*teamRecord{}
+			groups[g] = records
+		}
+		groupFixtures[g] = append(groupFixtures[g], f)
+
+		home := record(records, f.Teams.Home, g)
+		away := record(records, f.Teams.Away, g)
+		hg, ag := f.Goals.Home, f.Goals.Away
+		home.add(cfg, &home.ranking.Home, hg, ag, f)
+		away.add(cfg, &away.ranking.Away, ag, hg, f)
+		home.fairPlay += fairPlay(f, f.Teams.Home.ID)
+		away.fairPlay += fairPlay(f, f.Teams.Away.ID)
+	}
+
+	names := make([]string, 0, len(groups))
+	for g := range groups {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+
+	tables := make([]Table, 0, len(names))
+	for _, g := range names {
+		records := make([]*teamRecord, 0, len(groups[g]))
+		for _, r := range groups[g] {
+			r.ranking.Form = form(r.results, cfg.FormLength)
+			records = append(records, r)
+		}
+		rank(records, groupFixtures[g], cfg)
+
+		table := Table{Group: g, Rankings: make([]object.Ranking, len(records))}
+		for i, r := range records {
+			r.ranking.Rank = i + 1
+			table.Rankings[i] = r.ranking
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+func record(records map[int]*teamRecord, team object.FixtureTeam, group string) *teamRecord {
+	r, ok := records[team.ID]
+	if !ok {
+		r = &teamRecord{
+			ranking: object.Ranking{
+				Team:  object.TeamData{ID: team.ID, Name: team.Name, Logo: team.Logo},
+				Group: group,
+			},
+		}
+		records[team.ID] = r
+	}
+	return r
+}
+
+func (r *teamRecord) add(cfg Config, split *object.RankTotals, scored, conceded int, f object.FixtureDetail) {
+	for _, t := range []*object.RankTotals{&r.ranking.All, split} {
+		t.Played++
+		t.Goals.For += scored
+		t.Goals.Against += conceded
+		switch {
+		case scored > conceded:
+			t.Win++
+		case scored == conceded:
+			t.Draw++
+		default:
+			t.Lose++
+		}
+	}
+
+	switch {
+	case scored > conceded:
+		r.ranking.Points += cfg.Win
+		r.results = append(r.results, 'W')
+	case scored == conceded:
+		r.ranking.Points += cfg.Draw
+		r.results = append(r.results, 'D')
+	default:
+		r.ranking.Points += cfg.Loss
+		r.results = append(r.results, 'L')
+	}
+	r.ranking.Goalsdiff = r.ranking.All.Goals.For - r.ranking.All.Goals.Against
+	if f.Fixture.Date.After(r.ranking.Update.Time) {
+		r.ranking.Update = f.Fixture.Date
+	}
+}
+
+// form returns the last n results, the most recent last.
+func form(results []byte, n int) string {
+	if n > 0 && len(results) > n {
+		results = results[len(results)-n:]
+	}
+	return string(results)
+}
+
+func fairPlay(f object.FixtureDetail, teamID int) int {
+	points := 0
+	for _, e := range f.Events {
+		if e.Team.ID != teamID || !e.IsCard() {
+			continue
+		}
+		switch {
+		case e.IsYellowCard():
+			points++
+		case e.IsSecondYellow():
+			// The first yellow was already counted.
+			points += 2
+		case e.IsRedCard():
+			points += 4
+		}
+	}
+	return points
+}
+
+// rank sorts records by points and then by the tiebreakers.
+func rank(records []*teamRecord, fixtures []object.FixtureDetail, cfg Config) {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ranking.Team.Name < records[j].ranking.Team.Name
+	})
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ranking.Points > records[j].ranking.Points
+	})
+
+	start := 0
+	for i := 1; i <= len(records); i++ {
+		if i == len(records) || records[i].ranking.Points != records[start].ranking.Points {
+			breakTies(records[start:i], fixtures, cfg, cfg.Tiebreakers)
+			start = i
+		}
+	}
+}
+
+// breakTies orders teams that are tied by applying the first tiebreaker and then the
+// remaining ones to the teams that are still tied.
+func breakTies(tied []*teamRecord, fixtures []object.FixtureDetail, cfg Config, tiebreakers []Tiebreaker) {
+	if len(tied) < 2 || len(tiebreakers) == 0 {
+		return
+	}
+
+	keys := tiebreakKeys(tied, fixtures, cfg, tiebreakers[0])
+	sort.SliceStable(tied, func(i, j int) bool {
+		return less(keys[tied[j].ranking.Team.ID], keys[tied[i].ranking.Team.ID])
+	})
+
+	start := 0
+	for i := 1; i <= len(tied); i++ {
+		if i == len(tied) || less(keys[tied[i].ranking.Team.ID], keys[tied[start].ranking.Team.ID]) {
+			breakTies(tied[start:i], fixtures, cfg, tiebreakers[1:])
+			start = i
+		}
+	}
+}
+
+// tiebreakKeys returns, for each team, the values compared by the tiebreaker. Higher is better.
+func tiebreakKeys(tied []*teamRecord, fixtures []object.FixtureDetail, cfg Config, tb Tiebreaker) map[int][]int {
+	keys := make(map[int][]int, len(tied))
+	switch tb {
+	case GoalDifference:
+		for _, r := range tied {
+			keys[r.ranking.Team.ID] = []int{r.ranking.Goalsdiff}
+		}
+	case GoalsFor:
+		for _, r := range tied {
+			keys[r.ranking.Team.ID] = []int{r.ranking.All.Goals.For}
+		}
+	case AwayGoals:
+		for _, r := range tied {
+			keys[r.ranking.Team.ID] = []int{r.ranking.Away.Goals.For}
+		}
+	case FairPlay:
+		for _, r := range tied {
+			keys[r.ranking.Team.ID] = []int{-r.fairPlay}
+		}
+	case HeadToHead:
+		in := make(map[int]bool, len(tied))
+		for _, r := range tied {
+			in[r.ranking.Team.ID] = true
+			keys[r.ranking.Team.ID] = []int{0, 0, 0}
+		}
+		for _, f := range fixtures {
+			home, away := f.Teams.Home.ID, f.Teams.Away.ID
+			if !in[home] || !in[away] {
+				continue
+			}
+			addHeadToHead(keys[home], cfg, f.Goals.Home, f.Goals.Away)
+			addHeadToHead(keys[away], cfg, f.Goals.Away, f.Goals.Home)
+		}
+	}
+	return keys
+}
+
+func addHeadToHead(key []int, cfg Config, scored, conceded int) {
+	switch {
+	case scored > conceded:
+		key[0] += cfg.Win
+	case scored == conceded:
+		key[0] += cfg.Draw
+	default:
+		key[0] += cfg.Loss
+	}
+	key[1] += scored - conceded
+	key[2] += scored
+}
+
+// less compares keys lexicographically.
+func less(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
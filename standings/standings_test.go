@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package standings
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/avalonbits/fball/object"
+)
+
+var teamNames = map[int]string{1: "Alpha", 2: "Bravo", 3: "Charlie", 4: "Delta"}
+
+func fixture(ts int64, home, away, hg, ag int, events ...object.Event) object.FixtureDetail {
+	f := object.FixtureDetail{Events: events}
+	f.Fixture.ID = int(ts)
+	f.Fixture.Timestamp = ts
+	f.Fixture.Status.Short = object.StatusFinished
+	f.League.Round = "Regular Season - 1"
+	f.Teams.Home = object.FixtureTeam{ID: home, Name: teamNames[home]}
+	f.Teams.Away = object.FixtureTeam{ID: away, Name: teamNames[away]}
+	f.Goals.Home, f.Goals.Away = hg, ag
+	return f
+}
+
+func card(team int, detail object.EventDetail) object.Event {
+	e := object.Event{Type: object.EventCard, Detail: detail}
+	e.Team.ID = team
+	return e
+}
+
+// miniLeague has Alpha, Bravo and Charlie tied on 6 points. Among them Alpha has the best
+// head to head goal difference, then Charlie and then Bravo. Overall, Bravo has the best goal
+// difference, then Alpha and then Charlie.
+var miniLeague = []object.FixtureDetail{
+	fixture(1, 1, 2, 2, 0),
+	fixture(2, 2, 3, 1, 0),
+	fixture(3, 3, 1, 1, 0),
+	fixture(4, 1, 4, 1, 0),
+	fixture(5, 2, 4, 5, 0),
+	fixture(6, 3, 4, 1, 0),
+}
+
+func TestTiebreakers(t *testing.T) {
+	tests := []struct {
+		name        string
+		fixtures    []object.FixtureDetail
+		tiebreakers []Tiebreaker
+		want        []int
+	}{
+		{
+			name:        "head to head before goal difference",
+			fixtures:    miniLeague,
+			tiebreakers: []Tiebreaker{HeadToHead, GoalDifference},
+			want:        []int{1, 3, 2, 4},
+		},
+		{
+			name:        "goal difference before head to head",
+			fixtures:    miniLeague,
+			tiebreakers: []Tiebreaker{GoalDifference, HeadToHead},
+			want:        []int{2, 1, 3, 4},
+		},
+		{
+			name: "head to head between the teams still tied",
+			fixtures: []object.FixtureDetail{
+				fixture(1, 2, 1, 1, 0),
+				fixture(2, 1, 4, 2, 0),
+				fixture(3, 3, 4, 5, 0),
+			},
+			tiebreakers: []Tiebreaker{GoalDifference, HeadToHead},
+			want:        []int{3, 2, 1, 4},
+		},
+		{
+			name: "still tied teams are ordered by name",
+			fixtures: []object.FixtureDetail{
+				fixture(1, 3, 2, 1, 0),
+				fixture(2, 2, 1, 1, 0),
+				fixture(3, 1, 3, 1, 0),
+			},
+			tiebreakers: []Tiebreaker{GoalDifference, GoalsFor, HeadToHead, FairPlay},
+			want:        []int{1, 2, 3},
+		},
+		{
+			name: "fair play",
+			fixtures: []object.FixtureDetail{
+				fixture(1, 1, 2, 1, 1,
+					card(1, object.DetailYellowCard),
+					card(1, object.DetailYellowCard),
+					card(2, object.DetailYellowCard),
+				),
+			},
+			tiebreakers: []Tiebreaker{GoalDifference, HeadToHead, FairPlay},
+			want:        []int{2, 1},
+		},
+		{
+			name: "fair play scores two yellows below a straight red",
+			fixtures: []object.FixtureDetail{
+				fixture(1, 1, 2, 0, 0,
+					card(1, object.DetailRedCard),
+					card(2, object.DetailYellowCard),
+					card(2, "second yellow card"),
+				),
+			},
+			tiebreakers: []Tiebreaker{FairPlay},
+			want:        []int{2, 1},
+		},
+		{
+			name: "fair play scores a yellow and a straight red above two yellows",
+			fixtures: []object.FixtureDetail{
+				fixture(1, 1, 2, 0, 0,
+					card(1, object.DetailYellowCard),
+					card(1, object.DetailRedCard),
+					card(2, object.DetailYellowCard),
+					card(2, object.DetailSecondYellow),
+					card(2, object.DetailYellowCard),
+				),
+			},
+			tiebreakers: []Tiebreaker{FairPlay},
+			want:        []int{2, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Tiebreakers = tt.tiebreakers
+			tables := Build(tt.fixtures, cfg)
+			if len(tables) != 1 {
+				t.Fatalf("got %d tables, want 1", len(tables))
+			}
+
+			got := []int{}
+			for i, r := range tables[0].Rankings {
+				got = append(got, r.Team.ID)
+				if r.Rank != i+1 {
+					t.Errorf("team %d: got rank %d, want %d", r.Team.ID, r.Rank, i+1)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got order %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package timeline rebuilds the state of a game, minute by minute, from its events.
+package timeline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/avalonbits/fball/object"
+)
+
+// varWindow is how many minutes before a VAR goal cancellation the cancelled goal is looked for.
+const varWindow = 5
+
+// shootoutComment marks the events of a penalty shootout, which do not count for the score.
+const shootoutComment = "Penalty Shootout"
+
+// TeamState is the state of one of the teams.
+type TeamState struct {
+	Team        object.TeamData
+	Goals       int
+	YellowCards int
+	RedCards    int
+
+	// Players is the number of players on the pitch, which only goes down with red cards.
+	Players int
+
+	// OnPitch has the players on the pitch. It is only tracked when the team has a lineup.
+	OnPitch []object.Player
+}
+
+// State is the state of the game after an event.
+type State struct {
+	Elapsed int
+	Extra   int
+
+	// Event is the event that led to the state. It is the zero Event for the kick off state.
+	Event object.Event
+
+	Home TeamState
+	Away TeamState
+}
+
+// Advantage returns how many more players the home team has on the pitch than the away team.
+// It is negative if the away team has more.
+func (s State) Advantage() int {
+	return s.Home.Players - s.Away.Players
+}
+
+// Reversal is a goal cancelled by a VAR decision.
+type Reversal struct {
+	VAR object.Event
+
+	// Goal is the cancelled goal. Applied is false if no goal matched the decision, usually
+	// because the api had already removed it from the events, and the score was not changed.
+	Goal    object.Event
+	Applied bool
+}
+
+type stint struct {
+	from, to int
+}
+
+// Timeline is the sequence of states of a game.
+type Timeline struct {
+	Fixture object.FixtureDetail
+
+	// States has the kick off state followed by one state per event, in the order of
+	// object.CompareEvents.
+	States    []State
+	Reversals []Reversal
+
+	// Length is the number of minutes of the game, without time added at the end of a
+	// period, or the elapsed time if it is still being played.
+	Length int
+
+	// lineups has, for each side, whether its lineup is known. players has the side of each
+	// player found in the lineups or events.
+	lineups [2]bool
+	players map[int]int
+	stints  map[int][]stint
+}
+
+type goal struct {
+	event     object.Event
+	side      int
+	cancelled bool
+}
+
+// Build rebuilds the timeline of fixture f from its events, e.g. from Client.Event. Who is on
+// the pitch is tracked from f.Lineups, which are embedded in fixtures fetched by id or can be
+// set from Client.Lineup. Each team is tracked separately, so a team without a lineup only has
+// its number of players counted. Goals of a penalty shootout are ignored.
+func Build(f object.FixtureDetail, er object.EventResponse) (*Timeline, error) {
+	if f.Teams.Home.ID == 0 || f.Teams.Away.ID == 0 {
+		return nil, fmt.Errorf("fixture %d: missing teams", f.Fixture.ID)
+	}
+	events := append([]object.Event(nil), er.Event...)
+	object.SortEvents(events)
+
+	t := &Timeline{
+		Fixture: f,
+		Length:  f.Fixture.Status.Elapsed,
+		players: map[int]int{},
+		stints:  map[int][]stint{},
+	}
+	if t.Length == 0 && f.Fixture.Status.Short.IsFinished() {
+		t.Length = 90
+	}
+	for _, e := range events {
+		t.Length = max(t.Length, e.Time.Elapsed)
+	}
+
+	sides := map[int]int{f.Teams.Home.ID: 0, f.Teams.Away.ID: 1}
+	teams := [2]TeamState{
+		{Team: teamData(f.Teams.Home), Players: 11},
+		{Team: teamData(f.Teams.Away), Players: 11},
+	}
+	for _, l := range f.Lineups {
+		side, ok := sides[l.Team.ID]
+		if !ok || len(l.StartXI) == 0 {
+			continue
+		}
+		t.lineups[side] = true
+		teams[side].Players = len(l.StartXI)
+		for _, s := range l.StartXI {
+			teams[side].OnPitch = append(teams[side].OnPitch, s.Player)
+			t.stints[s.Player.ID] = []stint{{from: 0, to: -1}}
+			t.players[s.Player.ID] = side
+		}
+		for _, s := range l.Substitutes {
+			t.players[s.Player.ID] = side
+		}
+	}
+	t.States = append(t.States, State{Home: teams[0], Away: teams[1]})
+
+	goals := []*goal{}
+	for _, e := range events {
+		side, ok := sides[e.Team.ID]
+		if !ok {
+			continue
+		}
+		for _, p := range []object.Player{e.Player, e.Assist} {
+			playerSide := side
+			if e.IsOwnGoal() {
+				// The event team is the one that benefited from the own goal.
+				playerSide = 1 - side
+			}
+			if _, ok := t.players[p.ID]; p.ID != 0 && !ok {
+				t.players[p.ID] = playerSide
+			}
+		}
+		ts := &teams[side]
+		minute := min(e.Time.Elapsed, t.Length)
+		switch {
+		case e.IsGoal():
+			if strings.EqualFold(e.Comments, shootoutComment) {
+				continue
+			}
+			ts.Goals++
+			goals = append(goals, &goal{event: e, side: side})
+
+		case e.IsYellowCard():
+			ts.YellowCards++
+
+		case e.IsRedCard():
+			ts.RedCards++
+			if e.IsSecondYellow() {
+				ts.YellowCards++
+			}
+			if !t.lineups[side] {
+				ts.Players--
+			} else if i := indexOf(ts.OnPitch, e.Player.ID); i >= 0 {
+				ts.Players--
+				ts.OnPitch = remove(ts.OnPitch, i)
+				t.leave(e.Player.ID, minute)
+			}
+
+		case e.IsSubstitution():
+			if !t.lineups[side] {
+				break
+			}
+			out, in := e.Player, e.Assist
+			if indexOf(ts.OnPitch, out.ID) < 0 && indexOf(ts.OnPitch, in.ID) >= 0 {
+				out, in = in, out
+			}
+			if i := indexOf(ts.OnPitch, out.ID); i >= 0 {
+				ts.OnPitch = remove(ts.OnPitch, i)
+				t.leave(out.ID, minute)
+			}
+			if in.ID != 0 && indexOf(ts.OnPitch, in.ID) < 0 {
+				ts.OnPitch = append(append([]object.Player(nil), ts.OnPitch...), in)
+				t.stints[in.ID] = append(t.stints[in.ID], stint{from: minute, to: -1})
+			}
+
+		case e.IsVARDecision():
+			if !goalCancelled(e.Detail) {
+				break
+			}
+			rev := Reversal{VAR: e}
+			if g := cancelledGoal(goals, side, e); g != nil {
+				g.cancelled = true
+				ts.Goals--
+				rev.Goal, rev.Applied = g.event, true
+			}
+			t.Reversals = append(t.Reversals, rev)
+
+		default:
+			continue
+		}
+
+		t.States = append(t.States, State{
+			Elapsed: e.Time.Elapsed,
+			Extra:   e.Time.Extra,
+			Event:   e,
+			Home:    teams[0],
+			Away:    teams[1],
+		})
+	}
+	return t, nil
+}
+
+// cancelledGoal returns the latest goal of side that matches the VAR decision e, or nil.
+func cancelledGoal(goals []*goal, side int, e object.Event) *goal {
+	for i := len(goals) - 1; i >= 0; i-- {
+		g := goals[i]
+		if g.event.Time.Elapsed < e.Time.Elapsed-varWindow {
+			break
+		}
+		if g.cancelled || g.side != side {
+			continue
+		}
+		if e.Player.ID != 0 && g.event.Player.ID != e.Player.ID {
+			continue
+		}
+		return g
+	}
+	return nil
+}
+
+// goalCancelled returns true if a VAR decision cancelled a goal. Besides "Goal cancelled" the
+// api sends details like "Goal Disallowed - offside".
+func goalCancelled(d object.EventDetail) bool {
+	detail := strings.ToLower(string(d))
+	return detail == strings.ToLower(string(object.DetailGoalCancelled)) ||
+		strings.HasPrefix(detail, "goal disallowed")
+}
+
+func (t *Timeline) leave(player, minute int) {
+	s := t.stints[player]
+	if len(s) > 0 && s[len(s)-1].to < 0 {
+		s[len(s)-1].to = minute
+	}
+}
+
+// Final returns the state at the end of the timeline.
+func (t *Timeline) Final() State {
+	return t.States[len(t.States)-1]
+}
+
+// At returns the state after all events up to and including minute, counting the time added at
+// the end of a period as part of its last minute, e.g. At(45) includes the events at 45+2.
+func (t *Timeline) At(minute int) State {
+	s := t.States[0]
+	for _, st := range t.States[1:] {
+		if st.Elapsed > minute {
+			break
+		}
+		s = st
+	}
+	return s
+}
+
+// ScoreAt returns the score at minute. See At.
+func (t *Timeline) ScoreAt(minute int) (home, away int) {
+	s := t.At(minute)
+	return s.Home.Goals, s.Away.Goals
+}
+
+// MinutesPlayed returns the number of minutes player was on the pitch, without time added at
+// the end of a period. ok is false if the team of the player has no lineup, or if the player
+// is not in the fixture and either team has no lineup.
+func (t *Timeline) MinutesPlayed(player int) (minutes int, ok bool) {
+	side, found := t.players[player]
+	if found && !t.lineups[side] || !found && !(t.lineups[0] && t.lineups[1]) {
+		return 0, false
+	}
+	for _, s := range t.stints[player] {
+		to := s.to
+		if to < 0 {
+			to = t.Length
+		}
+		minutes += to - s.from
+	}
+	return minutes, true
+}
+
+// CheckScore returns an error if the final score of the timeline does not match the goals of
+// the fixture, e.g. because of missing events or a VAR decision that could not be applied.
+func (t *Timeline) CheckScore() error {
+	final := t.Final()
+	goals := t.Fixture.Goals
+	if final.Home.Goals != goals.Home || final.Away.Goals != goals.Away {
+		return fmt.Errorf("fixture %d: timeline score %d-%d does not match fixture score %d-%d",
+			t.Fixture.Fixture.ID, final.Home.Goals, final.Away.Goals, goals.Home, goals.Away)
+	}
+	return nil
+}
+
+func teamData(t object.FixtureTeam) object.TeamData {
+	return object.TeamData{ID: t.ID, Name: t.Name, Logo: t.Logo}
+}
+
+func indexOf(players []object.Player, id int) int {
+	if id == 0 {
+		return -1
+	}
+	for i, p := range players {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// remove returns a copy of players without the i-th one, so earlier states are not changed.
+func remove(players []object.Player, i int) []object.Player {
+	res := make([]object.Player, 0, len(players)-1)
+	res = append(res, players[:i]...)
+	return append(res, players[i+1:]...)
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (C) 2021  Igor Cananea <icc@avalonbits.com>
+ * Author: Igor Cananea <icc@avalonbits.com>
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package timeline
+
+import (
+	"testing"
+
+	"github.com/avalonbits/fball/object"
+)
+
+const (
+	home = 1
+	away = 2
+)
+
+func event(elapsed, extra, team int, typ object.EventType, detail object.EventDetail, player, assist int) object.Event {
+	e := object.Event{Type: typ, Detail: detail}
+	e.Time.Elapsed, e.Time.Extra = elapsed, extra
+	e.Team.ID = team
+	e.Player.ID, e.Assist.ID = player, assist
+	return e
+}
+
+// lineup returns a lineup of team whose starters are numbered team*100+1 to team*100+11 and
+// substitutes team*100+12 to team*100+18.
+func lineup(team int) object.Lineup {
+	l := object.Lineup{}
+	l.Team.ID = team
+	l.StartXI = make([]struct {
+		Player object.Player `json:"player"`
+	}, 11)
+	for i := range l.StartXI {
+		l.StartXI[i].Player.ID = team*100 + i + 1
+	}
+	l.Substitutes = make([]struct {
+		Player object.Player `json:"player"`
+	}, 7)
+	for i := range l.Substitutes {
+		l.Substitutes[i].Player.ID = team*100 + i + 12
+	}
+	return l
+}
+
+func fixture(homeGoals, awayGoals int, lineups ...object.Lineup) object.FixtureDetail {
+	f := object.FixtureDetail{Lineups: lineups}
+	f.Fixture.ID = 1
+	f.Fixture.Status.Short = object.StatusFinished
+	f.Fixture.Status.Elapsed = 90
+	f.Teams.Home.ID, f.Teams.Away.ID = home, away
+	f.Goals.Home, f.Goals.Away = homeGoals, awayGoals
+	return f
+}
+
+var events = object.EventResponse{Event: []object.Event{
+	// Sent out of order, and with the substitution players swapped.
+	event(70, 0, home, object.EventSubst, "Substitution 1", 112, 105),
+	event(10, 0, home, object.EventGoal, object.DetailNormalGoal, 109, 0),
+	event(45, 2, away, object.EventGoal, object.DetailPenalty, 209, 0),
+	event(47, 0, away, object.EventGoal, object.DetailNormalGoal, 210, 0),
+	event(49, 0, away, object.EventVar, object.DetailGoalCancelled, 210, 0),
+	event(55, 0, away, object.EventCard, object.DetailYellowCard, 204, 0),
+	event(60, 0, away, object.EventCard, object.DetailSecondYellow, 204, 0),
+	event(80, 0, home, object.EventGoal, object.DetailOwnGoal, 203, 0),
+}}
+
+func TestTimeline(t *testing.T) {
+	tl, err := Build(fixture(2, 1, lineup(home), lineup(away)), events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.CheckScore(); err != nil {
+		t.Error(err)
+	}
+	if len(tl.Reversals) != 1 || !tl.Reversals[0].Applied || tl.Reversals[0].Goal.Player.ID != 210 {
+		t.Errorf("got reversals %+v, want the goal of player 210 cancelled", tl.Reversals)
+	}
+
+	states := []struct {
+		minute     int
+		home, away int
+		advantage  int
+	}{
+		{0, 0, 0, 0},
+		{10, 1, 0, 0},
+		{45, 1, 1, 0},
+		{47, 1, 2, 0},
+		{49, 1, 1, 0},
+		{60, 1, 1, 1},
+		{90, 2, 1, 1},
+	}
+	for _, st := range states {
+		s := tl.At(st.minute)
+		if s.Home.Goals != st.home || s.Away.Goals != st.away || s.Advantage() != st.advantage {
+			t.Errorf("minute %d: got %d-%d with advantage %d, want %d-%d with advantage %d",
+				st.minute, s.Home.Goals, s.Away.Goals, s.Advantage(), st.home, st.away, st.advantage)
+		}
+	}
+
+	final := tl.Final()
+	if final.Away.YellowCards != 2 || final.Away.RedCards != 1 {
+		t.Errorf("got %d yellow and %d red cards, want 2 and 1", final.Away.YellowCards, final.Away.RedCards)
+	}
+	if len(final.Home.OnPitch) != 11 || len(final.Away.OnPitch) != 10 {
+		t.Errorf("got %d and %d players on the pitch, want 11 and 10", len(final.Home.OnPitch), len(final.Away.OnPitch))
+	}
+	if len(tl.States[0].Home.OnPitch) != 11 || len(tl.States[0].Away.OnPitch) != 11 {
+		t.Errorf("kick off state changed by later events")
+	}
+
+	minutes := []struct {
+		player int
+		want   int
+	}{
+		{101, 90},
+		{105, 70},
+		{112, 20},
+		{113, 0},
+		{204, 60},
+		{999, 0},
+	}
+	for _, m := range minutes {
+		got, ok := tl.MinutesPlayed(m.player)
+		if !ok || got != m.want {
+			t.Errorf("player %d: got %d minutes (ok %v), want %d", m.player, got, ok, m.want)
+		}
+	}
+}
+
+func TestTimelineOneLineup(t *testing.T) {
+	tl, err := Build(fixture(2, 1, lineup(home)), events)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	final := tl.Final()
+	if final.Away.Players != 10 || final.Advantage() != 1 {
+		t.Errorf("got %d away players and advantage %d, want 10 and 1", final.Away.Players, final.Advantage())
+	}
+	if len(final.Home.OnPitch) != 11 || len(final.Away.OnPitch) != 0 {
+		t.Errorf("got %d and %d players on the pitch, want 11 and 0", len(final.Home.OnPitch), len(final.Away.OnPitch))
+	}
+
+	tests := []struct {
+		player int
+		want   int
+		ok     bool
+	}{
+		{105, 70, true},
+		{112, 20, true},
+		{113, 0, true},
+		{203, 0, false},
+		{204, 0, false},
+		{999, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := tl.MinutesPlayed(tt.player)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("player %d: got %d minutes (ok %v), want %d (ok %v)", tt.player, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTimelineScoreMismatch(t *testing.T) {
+	tl, err := Build(fixture(3, 1, lineup(home), lineup(away)), events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.CheckScore(); err == nil {
+		t.Error("got no error for a final score that does not match the fixture")
+	}
+}